@@ -16,6 +16,7 @@ package ebml
 
 import (
 	"io"
+	"math"
 )
 
 // LacingMode is type of laced data.
@@ -27,6 +28,10 @@ const (
 	LacingXiph  LacingMode = 1
 	LacingFixed LacingMode = 2
 	LacingEBML  LacingMode = 3
+
+	// LacingAuto selects the lacing mode with the smallest size on MarshalBlock.
+	// It is never set by UnmarshalBlock.
+	LacingAuto LacingMode = 0xFF
 )
 
 const (
@@ -63,7 +68,62 @@ func (b *Block) packFlags() byte {
 }
 
 // UnmarshalBlock unmarshals EBML Block structure.
+// If r is a byte slice reader internally used by UnmarshalBytes,
+// frames are sub-slices of the source without copying.
 func UnmarshalBlock(r io.Reader, n int64) (*Block, error) {
+	b, ul, err := readBlockHeader(r, n)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		frame, err := ul.Read()
+		if err == io.EOF {
+			return b, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		b.Data = append(b.Data, frame)
+	}
+}
+
+// UnmarshalBlockBytes unmarshals EBML Block structure from b.
+// Frames are sub-slices of b without copying.
+func UnmarshalBlockBytes(b []byte) (*Block, error) {
+	return UnmarshalBlock(&byteSliceReader{b: b}, int64(len(b)))
+}
+
+// FrameOffset is the location of a frame in the Block binary.
+type FrameOffset struct {
+	Offset int
+	Size   int
+}
+
+// ParseBlockHeader parses the header of the Block binary including the lacing header.
+// It returns the Block without Data and the locations of the frames in b.
+// b must contain whole Block.
+func ParseBlockHeader(b []byte) (*Block, []FrameOffset, error) {
+	r := &byteSliceReader{b: b}
+	blk, ul, err := readBlockHeader(r, int64(len(b)))
+	if err != nil {
+		return nil, nil, err
+	}
+	size := ul.(*unlacer).size
+	frames := make([]FrameOffset, len(size))
+	off := len(b) - len(r.b)
+	for i, n := range size {
+		frames[i] = FrameOffset{Offset: off, Size: n}
+		off += n
+	}
+	if off > len(b) {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	return blk, frames, nil
+}
+
+// readBlockHeader reads the header of the Block and returns Unlacer
+// to read the frames.
+func readBlockHeader(r io.Reader, n int64) (*Block, Unlacer, error) {
 	var b Block
 	var err error
 	var nRead int
@@ -71,27 +131,27 @@ func UnmarshalBlock(r io.Reader, n int64) (*Block, error) {
 	vd := &valueDecoder{}
 
 	if b.TrackNumber, nRead, err = vd.readVUInt(r); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	n -= int64(nRead)
 	if v, err := vd.readInt(r, 2); err == nil {
 		b.Timecode = int16(v.(int64))
 	} else {
-		return nil, err
+		return nil, nil, err
 	}
 	n -= 2
 
 	switch _, err := r.Read(vd.bs[:]); err {
 	case nil:
 	case io.EOF:
-		return nil, io.ErrUnexpectedEOF
+		return nil, nil, io.ErrUnexpectedEOF
 	default:
-		return nil, err
+		return nil, nil, err
 	}
 	n--
 
 	if n < 0 {
-		return nil, io.ErrUnexpectedEOF
+		return nil, nil, io.ErrUnexpectedEOF
 	}
 
 	f := vd.bs[0]
@@ -118,23 +178,23 @@ func UnmarshalBlock(r io.Reader, n int64) (*Block, error) {
 		ul, err = NewFixedUnlacer(r, n)
 	}
 	if err != nil {
-		return nil, err
-	}
-
-	for {
-		frame, err := ul.Read()
-		if err == io.EOF {
-			return &b, nil
-		}
-		if err != nil {
-			return nil, err
-		}
-		b.Data = append(b.Data, frame)
+		return nil, nil, err
 	}
+	return &b, ul, nil
 }
 
 // MarshalBlock marshals EBML Block structure.
+// If b.Lacing is LacingAuto, the lacing mode with the smallest size is used.
 func MarshalBlock(b *Block, w io.Writer) error {
+	if b.Lacing == LacingAuto {
+		mode, _, err := selectLacing(b.Data)
+		if err != nil {
+			return err
+		}
+		bb := *b
+		bb.Lacing = mode
+		b = &bb
+	}
 	n, err := encodeElementID(b.TrackNumber)
 	if err != nil {
 		return err
@@ -159,6 +219,8 @@ func MarshalBlock(b *Block, w io.Writer) error {
 		l = NewEBMLLacer(w)
 	case LacingFixed:
 		l = NewFixedLacer(w)
+	default:
+		return wrapErrorf(ErrInvalidType, "lacing mode %d", b.Lacing)
 	}
 	if err := l.Write(b.Data); err != nil {
 		return err
@@ -166,3 +228,88 @@ func MarshalBlock(b *Block, w io.Writer) error {
 
 	return nil
 }
+
+// maxLacedFrames is the maximum number of frames in a laced block.
+const maxLacedFrames = 0xFF
+
+// LaceBudget is the limit of the block created by SplitLacedBlocks.
+// Zero means no limit.
+type LaceBudget struct {
+	// MaxBytes is the maximum size of the frames and the lacing header.
+	MaxBytes int
+	// MaxDuration is the maximum total duration of the frames
+	// in the unit of the Block timecode.
+	MaxDuration int64
+}
+
+// SplitLacedBlocks splits the frames of b into multiple blocks within the budget.
+// Each frame is frameDuration long in the unit of the Block timecode,
+// and the Timecode of the returned blocks is shifted by the duration of
+// the preceding frames.
+// Returned blocks have the flags of b and use LacingAuto.
+// A frame exceeding the budget by itself is stored in a block alone.
+func SplitLacedBlocks(b *Block, frameDuration int64, budget LaceBudget) ([]Block, error) {
+	var blocks []Block
+	var frames [][]byte
+	var first int
+
+	flush := func() error {
+		if len(frames) == 0 {
+			return nil
+		}
+		tc := int64(b.Timecode) + int64(first)*frameDuration
+		if tc < math.MinInt16 || math.MaxInt16 < tc {
+			return wrapErrorf(ErrOutOfRange, "block timecode %d", tc)
+		}
+		bb := *b
+		bb.Timecode = int16(tc)
+		bb.Lacing = LacingAuto
+		bb.Data = frames
+		blocks = append(blocks, bb)
+		frames = nil
+		return nil
+	}
+	fits := func(frames [][]byte) (bool, error) {
+		if len(frames) > maxLacedFrames {
+			return false, nil
+		}
+		if budget.MaxDuration > 0 && int64(len(frames))*frameDuration > budget.MaxDuration {
+			return false, nil
+		}
+		if budget.MaxBytes > 0 {
+			_, size, err := selectLacing(frames)
+			if err != nil {
+				return false, err
+			}
+			for _, f := range frames {
+				size += len(f)
+			}
+			if size > budget.MaxBytes {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
+	for i, f := range b.Data {
+		next := append(frames[:len(frames):len(frames)], f)
+		ok, err := fits(next)
+		if err != nil {
+			return nil, err
+		}
+		if !ok && len(frames) > 0 {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			next = [][]byte{f}
+		}
+		if len(frames) == 0 {
+			first = i
+		}
+		frames = next
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
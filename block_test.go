@@ -72,9 +72,75 @@ func TestUnmarshalBlock(t *testing.T) {
 				t.Errorf("Expected unmarshal result: '%v', got: '%v'", c.expected, *block)
 			}
 		})
+		t.Run(n+"Bytes", func(t *testing.T) {
+			input := append([]byte{}, c.input...)
+			block, err := UnmarshalBlockBytes(input)
+			if err != nil {
+				t.Fatalf("Failed to unmarshal block: '%v'", err)
+			}
+			if !reflect.DeepEqual(c.expected, *block) {
+				t.Errorf("Expected unmarshal result: '%v', got: '%v'", c.expected, *block)
+			}
+			last := block.Data[len(block.Data)-1]
+			if len(last) > 0 && &last[len(last)-1] != &input[len(input)-1] {
+				t.Error("Frame data must share the memory with the input")
+			}
+		})
 	}
 }
 
+func TestParseBlockHeader(t *testing.T) {
+	testCases := map[string]struct {
+		input    []byte
+		block    Block
+		expected []FrameOffset
+	}{
+		"NoLace": {
+			[]byte{0x82, 0x01, 0x23, 0x88, 0xAA, 0xCC},
+			Block{0x02, 0x0123, true, true, LacingNo, false, nil},
+			[]FrameOffset{{4, 2}},
+		},
+		"FixedLace": {
+			[]byte{0x82, 0x01, 0x23, 0x04, 0x02, 0x0A, 0x0B, 0x0C},
+			Block{0x02, 0x0123, false, false, LacingFixed, false, nil},
+			[]FrameOffset{{5, 1}, {6, 1}, {7, 1}},
+		},
+		"XiphLace": {
+			[]byte{0x82, 0x01, 0x23, 0x02, 0x02, 0x01, 0x02, 0x0A, 0x0B, 0x1B, 0x0C},
+			Block{0x02, 0x0123, false, false, LacingXiph, false, nil},
+			[]FrameOffset{{7, 1}, {8, 2}, {10, 1}},
+		},
+		"EBMLLace": {
+			[]byte{0x82, 0x01, 0x23, 0x06, 0x02, 0x81, 0xC0, 0x0A, 0x0B, 0x1B, 0x0C},
+			Block{0x02, 0x0123, false, false, LacingEBML, false, nil},
+			[]FrameOffset{{7, 1}, {8, 2}, {10, 1}},
+		},
+	}
+	for n, c := range testCases {
+		c := c
+		t.Run(n, func(t *testing.T) {
+			block, frames, err := ParseBlockHeader(c.input)
+			if err != nil {
+				t.Fatalf("Failed to parse block header: '%v'", err)
+			}
+			if !reflect.DeepEqual(c.block, *block) {
+				t.Errorf("Expected block: '%v', got: '%v'", c.block, *block)
+			}
+			if !reflect.DeepEqual(c.expected, frames) {
+				t.Errorf("Expected frames: '%v', got: '%v'", c.expected, frames)
+			}
+		})
+	}
+
+	t.Run("ShortData", func(t *testing.T) {
+		// Xiph lacing header says the first frame is 0x20 bytes.
+		input := []byte{0x82, 0x01, 0x23, 0x02, 0x01, 0x20, 0x0A, 0x0B}
+		if _, _, err := ParseBlockHeader(input); !errs.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Expected error: '%v', got: '%v'", io.ErrUnexpectedEOF, err)
+		}
+	})
+}
+
 func TestUnmarshalBlock_Error(t *testing.T) {
 	t.Run("EOF", func(t *testing.T) {
 		input := []byte{0x21, 0x23, 0x45, 0x00, 0x02, 0x00}
@@ -143,6 +209,14 @@ func TestMarshalBlock_Error(t *testing.T) {
 			&Block{0xFFFFFFFFFFFFFFFF, 0x0000, false, false, LacingNo, false, [][]byte{{}}},
 			ErrUnsupportedElementID,
 		},
+		"InvalidLacing": {
+			&Block{0x01, 0x0000, false, false, LacingMode(4), false, [][]byte{{}}},
+			ErrInvalidType,
+		},
+		"AutoTooManyFrames": {
+			&Block{0x01, 0x0000, false, false, LacingAuto, false, make([][]byte, 256)},
+			ErrTooManyFrames,
+		},
 	}
 	for name, c := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -161,3 +235,132 @@ func TestMarshalBlock_Error(t *testing.T) {
 		}
 	})
 }
+
+func TestMarshalBlock_LacingAuto(t *testing.T) {
+	testCases := map[string]struct {
+		frames   [][]byte
+		expected LacingMode
+	}{
+		"Single": {
+			frames:   [][]byte{{0x01, 0x02}},
+			expected: LacingNo,
+		},
+		"SameSize": {
+			frames:   [][]byte{{0x01, 0x02}, {0x03, 0x04}, {0x05, 0x06}},
+			expected: LacingFixed,
+		},
+		"SmallDifference": {
+			frames: [][]byte{
+				make([]byte, 10), make([]byte, 20), make([]byte, 30),
+			},
+			expected: LacingEBML,
+		},
+		"LargeDifference": {
+			frames: [][]byte{
+				make([]byte, 300), make([]byte, 10), make([]byte, 5),
+			},
+			expected: LacingXiph,
+		},
+		"XiphMultipleOf255": {
+			frames: [][]byte{
+				make([]byte, 255), make([]byte, 1), make([]byte, 1),
+			},
+			expected: LacingXiph,
+		},
+	}
+	for name, c := range testCases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			input := &Block{TrackNumber: 1, Lacing: LacingAuto, Data: c.frames}
+			var buf bytes.Buffer
+			if err := MarshalBlock(input, &buf); err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			if input.Lacing != LacingAuto {
+				t.Error("Input block must not be modified")
+			}
+			b, err := UnmarshalBlock(&buf, int64(buf.Len()))
+			if err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			if b.Lacing != c.expected {
+				t.Errorf("Expected lacing mode: %d, got: %d", c.expected, b.Lacing)
+			}
+			if !reflect.DeepEqual(c.frames, b.Data) {
+				t.Error("Unmarshalled frames differ from the input")
+			}
+		})
+	}
+}
+
+func TestSplitLacedBlocks(t *testing.T) {
+	frame := func(n int) []byte {
+		return make([]byte, n)
+	}
+	testCases := map[string]struct {
+		block         Block
+		frameDuration int64
+		budget        LaceBudget
+		expected      []Block
+		err           error
+	}{
+		"NoLimit": {
+			block:         Block{TrackNumber: 1, Timecode: 10, Keyframe: true, Data: [][]byte{frame(2), frame(2), frame(2)}},
+			frameDuration: 20,
+			expected: []Block{
+				{TrackNumber: 1, Timecode: 10, Keyframe: true, Lacing: LacingAuto, Data: [][]byte{frame(2), frame(2), frame(2)}},
+			},
+		},
+		"Bytes": {
+			// 2 frames with fixed lacing is 1+2+2 bytes.
+			block:         Block{TrackNumber: 1, Timecode: 10, Data: [][]byte{frame(2), frame(2), frame(2), frame(8)}},
+			frameDuration: 20,
+			budget:        LaceBudget{MaxBytes: 5},
+			expected: []Block{
+				{TrackNumber: 1, Timecode: 10, Lacing: LacingAuto, Data: [][]byte{frame(2), frame(2)}},
+				{TrackNumber: 1, Timecode: 50, Lacing: LacingAuto, Data: [][]byte{frame(2)}},
+				{TrackNumber: 1, Timecode: 70, Lacing: LacingAuto, Data: [][]byte{frame(8)}},
+			},
+		},
+		"Duration": {
+			block:         Block{TrackNumber: 2, Data: [][]byte{frame(1), frame(2), frame(3)}},
+			frameDuration: 20,
+			budget:        LaceBudget{MaxDuration: 40},
+			expected: []Block{
+				{TrackNumber: 2, Timecode: 0, Lacing: LacingAuto, Data: [][]byte{frame(1), frame(2)}},
+				{TrackNumber: 2, Timecode: 40, Lacing: LacingAuto, Data: [][]byte{frame(3)}},
+			},
+		},
+		"MaxFrames": {
+			block:         Block{TrackNumber: 1, Data: make([][]byte, 300)},
+			frameDuration: 1,
+			expected: []Block{
+				{TrackNumber: 1, Timecode: 0, Lacing: LacingAuto, Data: make([][]byte, 255)},
+				{TrackNumber: 1, Timecode: 255, Lacing: LacingAuto, Data: make([][]byte, 45)},
+			},
+		},
+		"TimecodeOverflow": {
+			block:         Block{TrackNumber: 1, Timecode: 0x7FF0, Data: [][]byte{frame(1), frame(1)}},
+			frameDuration: 0x20,
+			budget:        LaceBudget{MaxDuration: 0x20},
+			err:           ErrOutOfRange,
+		},
+	}
+	for name, c := range testCases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			blocks, err := SplitLacedBlocks(&c.block, c.frameDuration, c.budget)
+			if !errs.Is(err, c.err) {
+				t.Fatalf("Expected error: '%v', got: '%v'", c.err, err)
+			}
+			if !reflect.DeepEqual(c.expected, blocks) {
+				t.Errorf("Expected blocks:\n%+v\ngot:\n%+v", c.expected, blocks)
+			}
+			for _, b := range blocks {
+				if err := MarshalBlock(&b, &bytes.Buffer{}); err != nil {
+					t.Errorf("Failed to marshal split block: '%v'", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command ebmldiff shows the structural differences between two EBML files.
+//
+// Usage:
+//
+//	ebmldiff [flags] OLD NEW
+//
+// Exit status is 0 if no difference is found, 1 if some differences are found,
+// and 2 if an error occurred.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/at-wat/ebml-go"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	ignoreVolatile := flag.Bool("ignore-volatile", false, "ignore DateUTC, Void, CRC-32 and *UID elements")
+	ignore := flag.String("ignore", "", "comma separated names of the elements to ignore")
+	blocks := flag.Bool("blocks", false, "compare the summary of the blocks per track instead of each block")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] OLD NEW\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		return 2
+	}
+
+	var opts []ebml.DiffOption
+	if *ignoreVolatile {
+		opts = append(opts, ebml.WithIgnoreVolatile())
+	}
+	if *ignore != "" {
+		var types []ebml.ElementType
+		for _, name := range strings.Split(*ignore, ",") {
+			t, err := ebml.ElementTypeFromString(strings.TrimSpace(name))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+				return 2
+			}
+			types = append(types, t)
+		}
+		opts = append(opts, ebml.WithIgnoreElements(types...))
+	}
+	if *blocks {
+		opts = append(opts, ebml.WithBlockSummary())
+	}
+
+	a, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+	defer a.Close()
+	b, err := os.Open(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+	defer b.Close()
+
+	diffs, err := ebml.Diff(a, b, opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+	if len(diffs) > 0 {
+		return 1
+	}
+	return 0
+}
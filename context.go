@@ -0,0 +1,122 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"context"
+	"io"
+	"reflect"
+)
+
+// UnmarshalContext is Unmarshal stopped by ctx.
+//
+// The read is stopped before reading the next element after ctx is done,
+// and ctx.Err() is returned.
+// Sending to a channel field is also stopped.
+// A blocking read from r is not interrupted. Close r to stop it.
+func UnmarshalContext(ctx context.Context, r io.Reader, val interface{}, opts ...UnmarshalOption) error {
+	options, err := newUnmarshalOptions(opts)
+	if err != nil {
+		return err
+	}
+	// Bytes read ahead can be given back only if r is seekable.
+	d := newReaderDecoder(r, options, seekable(r))
+	err = d.DecodeContext(ctx, val)
+	if err == io.EOF {
+		err = nil
+	}
+	if errRelease := d.release(); errRelease != nil && (err == nil || err == ErrReadStopped) {
+		return errRelease
+	}
+	return err
+}
+
+// MarshalContext is Marshal stopped by ctx.
+//
+// The write is stopped before writing the next element after ctx is done,
+// and ctx.Err() is returned.
+// Receiving from a channel field is also stopped, and the values ready
+// in the channel are discarded to release the producer blocked on sending.
+// The values sent after that are not received, so the producer must stop
+// sending after ctx is done, e.g. by selecting ctx.Done().
+// The channel is not drained on the other errors.
+func MarshalContext(ctx context.Context, val interface{}, w io.Writer, opts ...MarshalOption) error {
+	return Marshal(val, w, append(opts, func(opts *MarshalOptions) error {
+		opts.ctx = ctx
+		return nil
+	})...)
+}
+
+// contextError returns ctx.Err() if ctx is done.
+// nil ctx is never done.
+func contextError(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
+
+// isContextError returns true if err is the error of done ctx.
+func isContextError(ctx context.Context, err error) bool {
+	return err != nil && err == contextError(ctx)
+}
+
+// sendContext sends v to the channel ch unless ctx is done.
+func sendContext(ctx context.Context, ch, v reflect.Value) error {
+	if ctx == nil || ctx.Done() == nil {
+		ch.Send(v)
+		return nil
+	}
+	chosen, _, _ := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		{Dir: reflect.SelectSend, Chan: ch, Send: v},
+	})
+	if chosen == 0 {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// recvContext receives a value from the channel ch unless ctx is done.
+func recvContext(ctx context.Context, ch reflect.Value) (reflect.Value, bool, error) {
+	if ctx == nil || ctx.Done() == nil {
+		v, ok := ch.Recv()
+		return v, ok, nil
+	}
+	chosen, v, ok := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		{Dir: reflect.SelectRecv, Chan: ch},
+	})
+	if chosen == 0 {
+		return reflect.Value{}, false, ctx.Err()
+	}
+	return v, ok, nil
+}
+
+// drainChan discards the values ready in the channel ch without blocking.
+// It doesn't wait for the producer to close ch not to leak the goroutine.
+func drainChan(ch reflect.Value) {
+	// Buffered values and a blocked sender can be received.
+	for i := 0; i <= ch.Cap(); i++ {
+		if _, ok := ch.TryRecv(); !ok {
+			return
+		}
+	}
+}
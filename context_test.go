@@ -0,0 +1,216 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/at-wat/ebml-go/internal/errs"
+)
+
+func TestUnmarshalContext(t *testing.T) {
+	b := []byte{
+		0x18, 0x53, 0x80, 0x67, 0x90, // Segment
+		0x1F, 0x43, 0xB6, 0x75, 0x83, // Cluster
+		0xE7, 0x81, 0x01, // Timecode
+		0x1F, 0x43, 0xB6, 0x75, 0x83, // Cluster
+		0xE7, 0x81, 0x02, // Timecode
+	}
+	type cluster struct {
+		Timecode uint64
+	}
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var ret struct {
+			Segment struct {
+				Cluster []cluster
+			}
+		}
+		if err := UnmarshalContext(ctx, bytes.NewReader(b), &ret); err != context.Canceled {
+			t.Errorf("Expected error: '%v', got: '%v'", context.Canceled, err)
+		}
+	})
+	t.Run("ChannelSend", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ch := make(chan cluster)
+		ret := struct {
+			Segment struct {
+				Cluster chan cluster
+			}
+		}{}
+		ret.Segment.Cluster = ch
+
+		errCh := make(chan error)
+		go func() {
+			errCh <- UnmarshalContext(ctx, bytes.NewReader(b), &ret)
+		}()
+		if c := <-ch; c.Timecode != 1 {
+			t.Errorf("Expected Timecode: 1, got: %d", c.Timecode)
+		}
+		cancel()
+
+		select {
+		case err := <-errCh:
+			if err != context.Canceled {
+				t.Errorf("Expected error: '%v', got: '%v'", context.Canceled, err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timeout")
+		}
+	})
+	t.Run("Background", func(t *testing.T) {
+		var ret struct {
+			Segment struct {
+				Cluster []cluster
+			}
+		}
+		if err := UnmarshalContext(context.Background(), bytes.NewReader(b), &ret); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		if n := len(ret.Segment.Cluster); n != 2 {
+			t.Errorf("Expected 2 Clusters, got %d", n)
+		}
+	})
+}
+
+func TestMarshalContext(t *testing.T) {
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		input := struct {
+			EBML struct {
+				DocType string
+			}
+		}{}
+		var buf bytes.Buffer
+		if err := MarshalContext(ctx, &input, &buf); err != context.Canceled {
+			t.Errorf("Expected error: '%v', got: '%v'", context.Canceled, err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Expected no output, got %d bytes", buf.Len())
+		}
+	})
+	t.Run("ChannelRecv", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ch := make(chan uint64)
+		input := struct {
+			Cluster struct {
+				Timecode chan uint64 `ebml:"Timecode"`
+			}
+		}{}
+		input.Cluster.Timecode = ch
+
+		errCh := make(chan error)
+		go func() {
+			errCh <- MarshalContext(ctx, &input, &bytes.Buffer{})
+		}()
+		ch <- 1
+		cancel()
+
+		select {
+		case err := <-errCh:
+			if err != context.Canceled {
+				t.Errorf("Expected error: '%v', got: '%v'", context.Canceled, err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timeout")
+		}
+
+		// Producer stopping on ctx.Done() must not be blocked after cancel.
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 3; i++ {
+				select {
+				case ch <- uint64(i):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Producer is blocked")
+		}
+	})
+	t.Run("ChannelDrainedOnCancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// The channel is never closed.
+		ch := make(chan uint64, 2)
+		ch <- 1
+		input := struct {
+			EBMLVersion chan uint64 `ebml:"EBMLVersion"`
+		}{ch}
+		hook := func(*Element) {
+			if ctx.Err() == nil {
+				cancel()
+				ch <- 2
+				ch <- 3
+			}
+		}
+
+		errCh := make(chan error)
+		go func() {
+			errCh <- MarshalContext(ctx, &input, &bytes.Buffer{}, WithElementWriteHooks(hook))
+		}()
+		select {
+		case err := <-errCh:
+			if err != context.Canceled {
+				t.Errorf("Expected error: '%v', got: '%v'", context.Canceled, err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timeout")
+		}
+		if n := len(ch); n != 0 {
+			t.Errorf("Values ready in the channel must be discarded, remaining: %d", n)
+		}
+	})
+	t.Run("ChannelNotDrainedOnError", func(t *testing.T) {
+		ch := make(chan interface{}, 3)
+		ch <- uint64(1)
+		ch <- "incompatible"
+		ch <- uint64(3)
+		close(ch)
+		input := struct {
+			Cluster struct {
+				Timecode chan interface{} `ebml:"Timecode"`
+			}
+		}{}
+		input.Cluster.Timecode = ch
+
+		if err := MarshalContext(context.Background(), &input, &bytes.Buffer{}); !errs.Is(err, ErrInvalidType) {
+			t.Fatalf("Expected error: '%v', got: '%v'", ErrInvalidType, err)
+		}
+		// Give the time to drain if it's wrongly started.
+		time.Sleep(50 * time.Millisecond)
+		if n := len(ch); n != 1 {
+			t.Errorf("Channel must not be drained on non-context error, remaining: %d", n)
+		}
+	})
+}
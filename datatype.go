@@ -77,3 +77,46 @@ func isConvertible(src, dst reflect.Type) bool {
 	}
 	return false
 }
+
+// overflows returns true if v is not representable in type t.
+// t must be convertible from the type of v.
+func overflows(v reflect.Value, t reflect.Type) bool {
+	z := reflect.Zero(t)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return z.OverflowInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return z.OverflowUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return z.OverflowFloat(v.Float())
+	}
+	return false
+}
+
+// isByteArray returns true if t is a fixed size byte array like [16]byte.
+func isByteArray(t reflect.Type) bool {
+	return t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8
+}
+
+// byteArrayDest returns the array type if t is a byte array or a slice of it.
+func byteArrayDest(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if isByteArray(t) {
+		return t
+	}
+	return nil
+}
+
+// bytesToArray copies b to the byte array of type t.
+func bytesToArray(b []byte, t reflect.Type) (reflect.Value, error) {
+	if len(b) != t.Len() {
+		return reflect.Value{}, wrapErrorf(
+			ErrInvalidElementSize, "unmarshalling %d bytes binary to %s", len(b), t,
+		)
+	}
+	v := reflect.New(t).Elem()
+	reflect.Copy(v, reflect.ValueOf(b))
+	return v, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"context"
+	"io"
+	"reflect"
+)
+
+// Decoder reads and decodes EBML elements from the stream.
+//
+// Unlike Unmarshal, Decoder keeps the read position, the bytes read ahead
+// and the decoding context like TimecodeScale across Decode calls.
+// Decode stopped by the stop tag can be resumed by the next Decode call
+// with a different destination.
+type Decoder struct {
+	r       io.Reader
+	br      *bufferedReader
+	vd      valueDecoder
+	options *UnmarshalOptions
+	pos     uint64
+	head    []byte
+	// depth is the depth of the element to be read next.
+	depth int
+}
+
+// NewDecoder creates Decoder reading from r.
+// If r doesn't implement io.ByteReader, r is internally buffered.
+func NewDecoder(r io.Reader, opts ...UnmarshalOption) (*Decoder, error) {
+	options, err := newUnmarshalOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return newReaderDecoder(r, options, true), nil
+}
+
+// newReaderDecoder creates Decoder reading from r.
+// r is buffered if buffer is true and r doesn't implement io.ByteReader.
+func newReaderDecoder(r io.Reader, options *UnmarshalOptions, buffer bool) *Decoder {
+	readerAt, offset := readerAtOf(r)
+
+	var d *Decoder
+	if _, ok := r.(io.ByteReader); ok {
+		d = newDecoder(r, options)
+	} else if !buffer {
+		d = newDecoder(&dataFirstReader{Reader: r}, options)
+	} else {
+		br := newBufferedReader(r)
+		d = newDecoder(br, options)
+		d.br = br
+	}
+	d.vd.readerAt, d.vd.offset = readerAt, offset
+	return d
+}
+
+func newDecoder(r io.Reader, options *UnmarshalOptions) *Decoder {
+	d := &Decoder{
+		r:       r,
+		options: options,
+	}
+	d.vd.strict = options.strict
+	return d
+}
+
+// Decode reads elements to val.
+//
+// Decode returns nil after reading all elements in the stream,
+// and io.EOF if no element is remaining.
+// If the read is stopped by the stop tag, ErrReadStopped is returned.
+// Following Decode call reads the elements after the stopped one to val,
+// including the remaining siblings of the stopped element and the elements
+// after its parents.
+// e.g. Segment children after Tracks can be read to struct{ Cluster []Cluster }
+// if the first Decode is stopped at Segment.Tracks.
+func (d *Decoder) Decode(val interface{}) error {
+	return d.DecodeContext(context.Background(), val)
+}
+
+// DecodeContext is Decode stopped by ctx.
+// ctx.Err() is returned if ctx is done during the read.
+// Decoder can't be resumed after that since the stream is stopped
+// in the middle of the element.
+func (d *Decoder) DecodeContext(ctx context.Context, val interface{}) error {
+	vo := reflect.ValueOf(val)
+	if !vo.IsValid() {
+		return wrapErrorf(ErrIndefiniteType, "unmarshalling to %T", val)
+	}
+	if vo.Kind() != reflect.Ptr {
+		return wrapErrorf(ErrIncompatibleType, "unmarshalling to %T", val)
+	}
+	voe := vo.Elem()
+
+	d.vd.ctx = ctx
+	defer func() {
+		d.vd.ctx = nil
+	}()
+
+	pos0 := d.pos
+	for {
+		r := d.r
+		if d.head != nil {
+			r = &prefixedReader{head: d.head, r: d.r}
+		}
+		head, end, err := d.vd.readElement(r, SizeUnknown, voe, d.depth, d.pos, nil, d.options)
+		d.head = head
+		d.pos = end
+		switch err {
+		case nil:
+		case ErrReadStopped:
+			d.depth = d.vd.stopDepth
+			return err
+		case io.EOF:
+			if d.head != nil && d.depth > 1 {
+				// Top level element following the stopped one is pending.
+				// Continue reading it as a Segment child.
+				d.depth = 1
+				continue
+			}
+			if d.pos == pos0 {
+				return io.EOF
+			}
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
+// Position returns the position of the next element in the stream.
+func (d *Decoder) Position() uint64 {
+	return d.pos
+}
+
+// release gives back the bytes read ahead by seeking the source
+// if the source implements io.Seeker.
+// Decoder must not be used after release.
+func (d *Decoder) release() error {
+	var err error
+	if d.br != nil {
+		src := d.br.src.Reader
+		err = d.br.release()
+		d.r, d.br = src, nil
+	}
+	if err == nil && len(d.head) > 0 {
+		if s, ok := d.r.(io.Seeker); ok {
+			_, err = s.Seek(-int64(len(d.head)), io.SeekCurrent)
+		}
+	}
+	return err
+}
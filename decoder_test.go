@@ -0,0 +1,220 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestDecoder_Resume(t *testing.T) {
+	type cluster struct {
+		Timecode uint64 `ebml:"Timecode"`
+	}
+	testCases := map[string]struct {
+		b            []byte
+		header       interface{}
+		stopPos      uint64
+		endPos       uint64
+		clusterPos   []uint64
+		timestampPos []uint64
+		clusters     []cluster
+	}{
+		"Sized": {
+			b: []byte{
+				0x18, 0x53, 0x80, 0x67, 0x95, // Segment
+				0x16, 0x54, 0xAE, 0x6B, 0x80, // Tracks
+				0x1F, 0x43, 0xB6, 0x75, 0x83, // Cluster
+				0xE7, 0x81, 0x01, // Timecode
+				0x1F, 0x43, 0xB6, 0x75, 0x83, // Cluster
+				0xE7, 0x81, 0x02, // Timecode
+			},
+			header: &struct {
+				Segment struct {
+					Tracks struct{} `ebml:"Tracks,stop"`
+				} `ebml:"Segment"`
+			}{},
+			stopPos:      10,
+			endPos:       26,
+			clusterPos:   []uint64{10, 18},
+			timestampPos: []uint64{15, 23},
+			clusters:     []cluster{{1}, {2}},
+		},
+		"UnknownSize": {
+			b: []byte{
+				0x18, 0x53, 0x80, 0x67, 0xFF, // Segment
+				0x1F, 0x43, 0xB6, 0x75, 0xFF, // Cluster
+				0xE7, 0x81, 0x01, // Timecode
+				0x1F, 0x43, 0xB6, 0x75, 0xFF, // Cluster
+				0xE7, 0x81, 0x02, // Timecode
+				0x1F, 0x43, 0xB6, 0x75, 0xFF, // Cluster
+				0xE7, 0x81, 0x03, // Timecode
+			},
+			header: &struct {
+				Segment struct {
+					Cluster cluster `ebml:"Cluster,stop"`
+				} `ebml:"Segment"`
+			}{},
+			stopPos:      13,
+			endPos:       29,
+			clusterPos:   []uint64{13, 21},
+			timestampPos: []uint64{18, 26},
+			clusters:     []cluster{{2}, {3}},
+		},
+	}
+	for name, c := range testCases {
+		t.Run(name, func(t *testing.T) {
+			runForEachReader(t, c.b, func(t *testing.T, r func() io.Reader) {
+				var clusterPos, timestampPos []uint64
+				hook := func(e *Element) {
+					switch e.Type {
+					case ElementCluster:
+						clusterPos = append(clusterPos, e.Position)
+					case ElementTimecode:
+						timestampPos = append(timestampPos, e.Position)
+					}
+				}
+				d, err := NewDecoder(r(), WithElementReadHooks(hook))
+				if err != nil {
+					t.Fatalf("Unexpected error: '%v'", err)
+				}
+				header := reflect.New(reflect.TypeOf(c.header).Elem()).Interface()
+				if err := d.Decode(header); err != ErrReadStopped {
+					t.Fatalf("Expected error: '%v', got: '%v'", ErrReadStopped, err)
+				}
+				if pos := d.Position(); pos != c.stopPos {
+					t.Errorf("Expected stop position: %d, got: %d", c.stopPos, pos)
+				}
+
+				clusterPos, timestampPos = nil, nil
+				var clusters struct {
+					Cluster []cluster `ebml:"Cluster"`
+				}
+				if err := d.Decode(&clusters); err != nil {
+					t.Fatalf("Unexpected error: '%v'", err)
+				}
+				expected := c.clusters
+				if !reflect.DeepEqual(expected, clusters.Cluster) {
+					t.Errorf("Expected clusters: %v, got: %v", expected, clusters.Cluster)
+				}
+				if pos := d.Position(); pos != c.endPos {
+					t.Errorf("Expected end position: %d, got: %d", c.endPos, pos)
+				}
+				if !reflect.DeepEqual(c.clusterPos, clusterPos) {
+					t.Errorf("Expected Cluster positions: %v, got: %v", c.clusterPos, clusterPos)
+				}
+				if !reflect.DeepEqual(c.timestampPos, timestampPos) {
+					t.Errorf("Expected Timecode positions: %v, got: %v", c.timestampPos, timestampPos)
+				}
+
+				if err := d.Decode(&clusters); err != io.EOF {
+					t.Errorf("Expected error: '%v', got: '%v'", io.EOF, err)
+				}
+			})
+		})
+	}
+}
+
+func TestDecoder_ResumeNested(t *testing.T) {
+	type cluster struct {
+		Timecode uint64 `ebml:"Timecode"`
+	}
+	type header struct {
+		Segment struct {
+			Cluster struct {
+				Timecode uint64 `ebml:"Timecode,stop"`
+			} `ebml:"Cluster"`
+		} `ebml:"Segment"`
+	}
+	type rest struct {
+		PrevSize uint64    `ebml:"PrevSize"`
+		Cluster  []cluster `ebml:"Cluster"`
+	}
+	testCases := map[string]struct {
+		b       []byte
+		stopPos uint64
+		endPos  uint64
+	}{
+		"Sized": {
+			b: []byte{
+				0x18, 0x53, 0x80, 0x67, 0x96, // Segment
+				0x1F, 0x43, 0xB6, 0x75, 0x86, // Cluster
+				0xE7, 0x81, 0x01, // Timecode
+				0xAB, 0x81, 0x05, // PrevSize
+				0x1F, 0x43, 0xB6, 0x75, 0x83, // Cluster
+				0xE7, 0x81, 0x02, // Timecode
+			},
+			stopPos: 13,
+			endPos:  24,
+		},
+		"UnknownSize": {
+			b: []byte{
+				0x18, 0x53, 0x80, 0x67, 0xFF, // Segment
+				0x1F, 0x43, 0xB6, 0x75, 0xFF, // Cluster
+				0xE7, 0x81, 0x01, // Timecode
+				0xAB, 0x81, 0x05, // PrevSize
+				0x1F, 0x43, 0xB6, 0x75, 0xFF, // Cluster
+				0xE7, 0x81, 0x02, // Timecode
+			},
+			stopPos: 13,
+			endPos:  24,
+		},
+	}
+	for name, c := range testCases {
+		t.Run(name, func(t *testing.T) {
+			runForEachReader(t, c.b, func(t *testing.T, r func() io.Reader) {
+				d, err := NewDecoder(r())
+				if err != nil {
+					t.Fatalf("Unexpected error: '%v'", err)
+				}
+				var h header
+				if err := d.Decode(&h); err != ErrReadStopped {
+					t.Fatalf("Expected error: '%v', got: '%v'", ErrReadStopped, err)
+				}
+				if h.Segment.Cluster.Timecode != 1 {
+					t.Errorf("Expected Timecode: 1, got: %d", h.Segment.Cluster.Timecode)
+				}
+				if pos := d.Position(); pos != c.stopPos {
+					t.Errorf("Expected stop position: %d, got: %d", c.stopPos, pos)
+				}
+
+				var v rest
+				if err := d.Decode(&v); err != nil {
+					t.Fatalf("Unexpected error: '%v'", err)
+				}
+				expected := rest{PrevSize: 5, Cluster: []cluster{{2}}}
+				if !reflect.DeepEqual(expected, v) {
+					t.Errorf("Expected: %+v, got: %+v", expected, v)
+				}
+				if pos := d.Position(); pos != c.endPos {
+					t.Errorf("Expected end position: %d, got: %d", c.endPos, pos)
+				}
+
+				if err := d.Decode(&v); err != io.EOF {
+					t.Errorf("Expected error: '%v', got: '%v'", io.EOF, err)
+				}
+			})
+		})
+	}
+}
+
+func TestDecoder_OptionError(t *testing.T) {
+	errExpected := ErrIndefiniteType
+	_, err := NewDecoder(nil, func(*UnmarshalOptions) error { return errExpected })
+	if err != errExpected {
+		t.Errorf("Expected error: '%v', got: '%v'", errExpected, err)
+	}
+}
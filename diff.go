@@ -0,0 +1,586 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"time"
+)
+
+// DiffKind represents the kind of Difference.
+type DiffKind int
+
+// DiffKind values.
+const (
+	DiffAdded DiffKind = iota + 1
+	DiffRemoved
+	DiffChanged
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffChanged:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// Difference represents a difference of an element between two EBML documents.
+type Difference struct {
+	Kind DiffKind
+	// Path is the path of the element like "Segment/Cluster[2]/Timestamp".
+	// Index is added if the element appears multiple times in the parent.
+	// Block summary is located at "Segment/Blocks[track=N]".
+	// Unknown element is located by its ID like "Segment/0x5FFF".
+	Path string
+	// Type is ElementInvalid for the block summary and the unknown elements.
+	Type ElementType
+	// Old and New are the values of the element in the old and new documents.
+	// nil if the element doesn't exist or is a master element.
+	// Binary data longer than 32 bytes is represented by BinaryDigest,
+	// and summary of the blocks is represented by BlockSummary.
+	Old, New interface{}
+	// OldPosition and NewPosition are the absolute offsets of the element
+	// in the old and new documents.
+	OldPosition, NewPosition uint64
+}
+
+func (d Difference) String() string {
+	switch d.Kind {
+	case DiffAdded:
+		return fmt.Sprintf("+ %s at %d%s", d.Path, d.NewPosition, formatDiffValue(d.New))
+	case DiffRemoved:
+		return fmt.Sprintf("- %s at %d%s", d.Path, d.OldPosition, formatDiffValue(d.Old))
+	default:
+		return fmt.Sprintf("~ %s at %d/%d: %s -> %s",
+			d.Path, d.OldPosition, d.NewPosition,
+			formatValue(d.Old), formatValue(d.New),
+		)
+	}
+}
+
+func formatDiffValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return ": " + formatValue(v)
+}
+
+func formatValue(v interface{}) string {
+	if b, ok := v.([]byte); ok {
+		return fmt.Sprintf("0x%x", b)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// diffBinaryMaxLen is the maximum length of the binary compared and shown as is.
+const diffBinaryMaxLen = 32
+
+// BinaryDigest represents binary data in Difference.
+type BinaryDigest struct {
+	Size   uint64
+	SHA256 [sha256.Size]byte
+}
+
+func (d BinaryDigest) String() string {
+	return fmt.Sprintf("%d bytes (sha256:%x)", d.Size, d.SHA256[:8])
+}
+
+// BlockSummary is the summary of the blocks of a track in a Segment.
+type BlockSummary struct {
+	TrackNumber uint64
+	Blocks      int
+	Keyframes   int
+	Frames      int
+	// Bytes is the total size of the frames.
+	Bytes uint64
+	// FirstTimestamp and LastTimestamp are the timestamps of the first and
+	// the last blocks in the unit of TimecodeScale.
+	FirstTimestamp, LastTimestamp int64
+	// SHA256 is the hash of the timestamps and the frames.
+	SHA256 [sha256.Size]byte
+}
+
+func (s BlockSummary) String() string {
+	return fmt.Sprintf(
+		"%d blocks (%d keyframes), %d frames, %d bytes, timestamp %d-%d (sha256:%x)",
+		s.Blocks, s.Keyframes, s.Frames, s.Bytes,
+		s.FirstTimestamp, s.LastTimestamp, s.SHA256[:8],
+	)
+}
+
+// DiffOption configures a DiffOptions struct.
+type DiffOption func(*DiffOptions) error
+
+// DiffOptions stores options for Diff.
+type DiffOptions struct {
+	ignore       map[ElementType]bool
+	blockSummary bool
+}
+
+// WithIgnoreElements returns a DiffOption which excludes the elements from the comparison.
+func WithIgnoreElements(types ...ElementType) DiffOption {
+	return func(opts *DiffOptions) error {
+		for _, t := range types {
+			opts.ignore[t] = true
+		}
+		return nil
+	}
+}
+
+// WithIgnoreVolatile returns a DiffOption which excludes the elements
+// differs in each encoding from the comparison:
+// DateUTC, Void, CRC-32 and the elements named *UID.
+func WithIgnoreVolatile() DiffOption {
+	return func(opts *DiffOptions) error {
+		opts.ignore[ElementDateUTC] = true
+		opts.ignore[ElementVoid] = true
+		opts.ignore[ElementCRC32] = true
+		for t := range table {
+			if strings.HasSuffix(t.String(), "UID") {
+				opts.ignore[t] = true
+			}
+		}
+		return nil
+	}
+}
+
+// WithBlockSummary returns a DiffOption which compares the summary of
+// the blocks of each track in the Segment instead of the each block.
+// BlockGroups are also excluded from the comparison.
+// Blocks in the elements excluded by WithIgnoreElements are not summarized.
+func WithBlockSummary() DiffOption {
+	return func(opts *DiffOptions) error {
+		opts.blockSummary = true
+		return nil
+	}
+}
+
+// Diff reads two EBML documents and returns the differences.
+// Elements are aligned by the path from the root.
+// Added and removed master elements are reported without their children.
+// Unknown elements are compared as binary and aligned by the element ID.
+func Diff(a, b io.Reader, opts ...DiffOption) ([]Difference, error) {
+	options := &DiffOptions{
+		ignore: make(map[ElementType]bool),
+	}
+	for _, o := range opts {
+		if err := o(options); err != nil {
+			return nil, err
+		}
+	}
+
+	ta, err := readDiffTree(a, options)
+	if err != nil {
+		return nil, err
+	}
+	tb, err := readDiffTree(b, options)
+	if err != nil {
+		return nil, err
+	}
+	var diffs []Difference
+	diffChildren(&diffs, "", ta, tb)
+	return diffs, nil
+}
+
+// diffNode is an element in the tree compared by Diff.
+type diffNode struct {
+	t ElementType
+	// id is the element ID of the unknown element.
+	id  uint32
+	pos uint64
+	// value is nil for the master elements.
+	value    interface{}
+	children []*diffNode
+}
+
+// diffHeader is the header of the element.
+type diffHeader struct {
+	element
+	id         uint32
+	pos        uint64
+	size       uint64
+	headerSize uint64
+}
+
+// diffTreeReader reads the element tree to be compared.
+type diffTreeReader struct {
+	vd      valueDecoder
+	options *DiffOptions
+	// next is the header read ahead by the child of the unknown-size element.
+	next *diffHeader
+	// timestamp is the Timestamp of the current Cluster.
+	timestamp uint64
+	// summary stores the block summaries of the current Segment.
+	summary *diffBlockSummaries
+	// ignored is the depth of the ignored unknown-size elements being read.
+	// Blocks in the ignored elements are not summarized.
+	ignored int
+}
+
+type diffBlockSummaries struct {
+	tracks  []uint64
+	byTrack map[uint64]*blockSummaryBuilder
+}
+
+type blockSummaryBuilder struct {
+	BlockSummary
+	pos uint64
+	h   hash.Hash
+}
+
+func readDiffTree(r io.Reader, options *DiffOptions) (*diffNode, error) {
+	if _, ok := r.(io.ByteReader); !ok {
+		br := newBufferedReader(r)
+		defer func() {
+			_ = br.release()
+		}()
+		r = br
+	}
+	tr := &diffTreeReader{options: options}
+	root := &diffNode{}
+	tr.summary = &diffBlockSummaries{byTrack: make(map[uint64]*blockSummaryBuilder)}
+	if _, err := tr.readChildren(r, root, SizeUnknown, 0, 0); err != nil {
+		return nil, err
+	}
+	tr.summary.appendTo(root)
+	return root, nil
+}
+
+func (tr *diffTreeReader) readHeader(r io.Reader, pos uint64) (*diffHeader, error) {
+	if h := tr.next; h != nil {
+		tr.next = nil
+		return h, nil
+	}
+	e, nb, err := tr.vd.readVUInt(r)
+	if err != nil {
+		if nb == 0 && err == io.ErrUnexpectedEOF {
+			return nil, io.EOF
+		}
+		return nil, &ElementError{Err: err, Position: pos}
+	}
+	id := uint32(e) | 1<<uint(7*nb)
+	v, ok := revTable[uint32(e)]
+	if !ok {
+		// Unknown element is compared as binary.
+		v = element{e: ElementInvalid, t: DataTypeBinary}
+	}
+	size, ns, err := tr.vd.readDataSize(r)
+	if err == nil && !ok && size == SizeUnknown {
+		err = wrapErrorf(ErrUnknownElement, "reading unknown-size element 0x%x", id)
+	}
+	if err != nil {
+		return nil, &ElementError{Err: err, Path: diffElementName(v.e, id), Position: pos, ID: id, Type: v.e}
+	}
+	return &diffHeader{
+		element:    v,
+		id:         id,
+		pos:        pos,
+		size:       size,
+		headerSize: uint64(nb + ns),
+	}, nil
+}
+
+// readChildren reads the children of the master element to parent
+// and returns the position of the end of the children.
+func (tr *diffTreeReader) readChildren(r io.Reader, parent *diffNode, n uint64, pos uint64, depth int) (uint64, error) {
+	if n != SizeUnknown {
+		r = &limitedReader{r: r, n: int64(n)}
+	}
+	for {
+		h, err := tr.readHeader(r, pos)
+		if err == io.EOF {
+			return pos, nil
+		}
+		if err != nil {
+			return pos, err
+		}
+		if n == SizeUnknown && h.t == DataTypeMaster && h.top && depth > 1 {
+			// Top level element terminates the unknown-size parent.
+			tr.next = h
+			return pos, nil
+		}
+		node, end, err := tr.readElement(r, h, depth)
+		if err != nil {
+			if _, ok := err.(*ElementError); ok {
+				return pos, err
+			}
+			return pos, &ElementError{Err: err, Path: diffElementName(h.e, h.id), Position: h.pos, ID: h.id, Type: h.e}
+		}
+		if node != nil {
+			parent.children = append(parent.children, node)
+		}
+		pos = end
+	}
+}
+
+// readElement reads the element data and returns the position of the end of the element.
+// nil node is returned if the element is excluded from the tree.
+func (tr *diffTreeReader) readElement(r io.Reader, h *diffHeader, depth int) (*diffNode, uint64, error) {
+	node := &diffNode{t: h.e, pos: h.pos}
+	if h.e == ElementInvalid {
+		node.id = h.id
+	}
+	dataPos := h.pos + h.headerSize
+	end := dataPos + h.size
+
+	switch {
+	case tr.options.ignore[h.e] && (h.t != DataTypeMaster || h.size != SizeUnknown):
+		// Ignored element is skipped including the children.
+		return nil, end, skip(r, h.size)
+	case h.t == DataTypeMaster:
+		if tr.options.ignore[h.e] {
+			// Children of the unknown-size element must be read to find the end.
+			tr.ignored++
+			defer func() {
+				tr.ignored--
+			}()
+		}
+		var summary *diffBlockSummaries
+		switch h.e {
+		case ElementSegment:
+			summary = tr.summary
+			tr.summary = &diffBlockSummaries{byTrack: make(map[uint64]*blockSummaryBuilder)}
+		case ElementCluster:
+			tr.timestamp = 0
+		}
+		childrenEnd, err := tr.readChildren(r, node, h.size, dataPos, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		if h.size == SizeUnknown {
+			end = childrenEnd
+		}
+		if summary != nil {
+			tr.summary.appendTo(node)
+			tr.summary = summary
+		}
+		if tr.options.ignore[h.e] || (tr.options.blockSummary && h.e == ElementBlockGroup) {
+			return nil, end, nil
+		}
+		return node, end, nil
+	case h.t == DataTypeBlock && tr.options.blockSummary:
+		if tr.ignored > 0 {
+			return nil, end, skip(r, h.size)
+		}
+		b, err := UnmarshalBlock(r, int64(h.size))
+		if err != nil {
+			return nil, 0, err
+		}
+		tr.summary.add(b, int64(tr.timestamp), h.pos)
+		return nil, end, nil
+	case h.t == DataTypeBlock || (h.t == DataTypeBinary && h.size > diffBinaryMaxLen):
+		d := BinaryDigest{Size: h.size}
+		s := sha256.New()
+		if _, err := io.CopyN(s, r, int64(h.size)); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, 0, err
+		}
+		copy(d.SHA256[:], s.Sum(nil))
+		node.value = d
+		return node, end, nil
+	default:
+		v, err := tr.vd.decode(h.t, r, h.size)
+		if err != nil {
+			return nil, 0, err
+		}
+		if h.e == ElementTimestamp {
+			tr.timestamp = v.(uint64)
+		}
+		node.value = v
+		return node, end, nil
+	}
+}
+
+func (s *diffBlockSummaries) add(b *Block, clusterTimestamp int64, pos uint64) {
+	sb, ok := s.byTrack[b.TrackNumber]
+	if !ok {
+		sb = &blockSummaryBuilder{
+			BlockSummary: BlockSummary{TrackNumber: b.TrackNumber},
+			pos:          pos,
+			h:            sha256.New(),
+		}
+		s.byTrack[b.TrackNumber] = sb
+		s.tracks = append(s.tracks, b.TrackNumber)
+	}
+	ts := clusterTimestamp + int64(b.Timecode)
+	if sb.Blocks == 0 {
+		sb.FirstTimestamp = ts
+	}
+	sb.LastTimestamp = ts
+	sb.Blocks++
+	if b.Keyframe {
+		sb.Keyframes++
+	}
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(ts))
+	_, _ = sb.h.Write(buf[:])
+	for _, f := range b.Data {
+		sb.Frames++
+		sb.Bytes += uint64(len(f))
+		binary.BigEndian.PutUint64(buf[:], uint64(len(f)))
+		_, _ = sb.h.Write(buf[:])
+		_, _ = sb.h.Write(f)
+	}
+}
+
+// appendTo appends the summaries to the node as pseudo elements.
+func (s *diffBlockSummaries) appendTo(node *diffNode) {
+	for _, track := range s.tracks {
+		sb := s.byTrack[track]
+		copy(sb.SHA256[:], sb.h.Sum(nil))
+		node.children = append(node.children, &diffNode{
+			t:     ElementInvalid,
+			pos:   sb.pos,
+			value: sb.BlockSummary,
+		})
+	}
+}
+
+// diffKey is the key to align the elements.
+type diffKey struct {
+	t ElementType
+	// id is the element ID of the unknown element.
+	id uint32
+	// track is the track number of the block summary.
+	track uint64
+	index int
+}
+
+// keys returns the keys of the children, the children by the key and
+// the number of the children by the key without index.
+func (n *diffNode) keys() ([]diffKey, map[diffKey]*diffNode, map[diffKey]int) {
+	keys := make([]diffKey, 0, len(n.children))
+	nodes := make(map[diffKey]*diffNode, len(n.children))
+	counts := make(map[diffKey]int)
+	for _, c := range n.children {
+		k := diffKey{t: c.t, id: c.id}
+		if s, ok := c.value.(BlockSummary); ok && c.t == ElementInvalid {
+			k.track = s.TrackNumber
+		}
+		i := counts[k]
+		counts[k]++
+		k.index = i
+		keys = append(keys, k)
+		nodes[k] = c
+	}
+	return keys, nodes, counts
+}
+
+func diffPath(parent string, k diffKey, repeated bool) string {
+	var name string
+	switch {
+	case k.id != 0:
+		name = diffElementName(k.t, k.id)
+		if repeated || k.index > 0 {
+			name += fmt.Sprintf("[%d]", k.index)
+		}
+	case k.t == ElementInvalid:
+		name = fmt.Sprintf("Blocks[track=%d]", k.track)
+	default:
+		name = elementPathName(k.t, k.index, repeated)
+	}
+	if parent == "" {
+		return name
+	}
+	return parent + "/" + name
+}
+
+// diffElementName returns the name of the element in the path.
+// Unknown element is named by its ID.
+func diffElementName(t ElementType, id uint32) string {
+	if t == ElementInvalid {
+		return fmt.Sprintf("0x%X", id)
+	}
+	return t.String()
+}
+
+func diffChildren(diffs *[]Difference, path string, a, b *diffNode) {
+	keysA, nodesA, countsA := a.keys()
+	keysB, nodesB, countsB := b.keys()
+
+	repeated := func(k diffKey) bool {
+		k.index = 0
+		return countsA[k] > 1 || countsB[k] > 1
+	}
+	for _, k := range keysA {
+		na := nodesA[k]
+		p := diffPath(path, k, repeated(k))
+		nb, ok := nodesB[k]
+		if !ok {
+			*diffs = append(*diffs, Difference{
+				Kind:        DiffRemoved,
+				Path:        p,
+				Type:        k.t,
+				Old:         na.value,
+				OldPosition: na.pos,
+			})
+			continue
+		}
+		if na.value == nil && nb.value == nil {
+			diffChildren(diffs, p, na, nb)
+			continue
+		}
+		if !diffValueEqual(na.value, nb.value) {
+			*diffs = append(*diffs, Difference{
+				Kind:        DiffChanged,
+				Path:        p,
+				Type:        k.t,
+				Old:         na.value,
+				New:         nb.value,
+				OldPosition: na.pos,
+				NewPosition: nb.pos,
+			})
+		}
+	}
+	for _, k := range keysB {
+		if _, ok := nodesA[k]; ok {
+			continue
+		}
+		nb := nodesB[k]
+		*diffs = append(*diffs, Difference{
+			Kind:        DiffAdded,
+			Path:        diffPath(path, k, repeated(k)),
+			Type:        k.t,
+			New:         nb.value,
+			NewPosition: nb.pos,
+		})
+	}
+}
+
+func diffValueEqual(a, b interface{}) bool {
+	switch va := a.(type) {
+	case []byte:
+		vb, ok := b.([]byte)
+		return ok && bytes.Equal(va, vb)
+	case time.Time:
+		vb, ok := b.(time.Time)
+		return ok && va.Equal(vb)
+	}
+	return a == b
+}
@@ -0,0 +1,296 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/at-wat/ebml-go/internal/errs"
+)
+
+func TestDiff(t *testing.T) {
+	type trackEntry struct {
+		TrackNumber uint64
+		TrackUID    uint64
+		CodecID     string
+	}
+	type cluster struct {
+		Timecode    uint64
+		SimpleBlock []Block
+	}
+	type segment struct {
+		Info struct {
+			TimecodeScale uint64
+			DateUTC       time.Time
+		}
+		Tracks struct {
+			TrackEntry []trackEntry
+		}
+		Cluster []cluster
+	}
+	type document struct {
+		Segment segment
+	}
+	type documentUnknownSize struct {
+		Segment struct {
+			Info struct {
+				TimecodeScale uint64
+				DateUTC       time.Time
+			}
+			Tracks struct {
+				TrackEntry []trackEntry
+			}
+			Cluster []cluster `ebml:"Cluster,size=unknown"`
+		} `ebml:"Segment,size=unknown"`
+	}
+
+	newDoc := func() *document {
+		doc := &document{}
+		doc.Segment.Info.TimecodeScale = 1000000
+		doc.Segment.Info.DateUTC = time.Unix(1600000000, 0)
+		doc.Segment.Tracks.TrackEntry = []trackEntry{
+			{TrackNumber: 1, TrackUID: 10, CodecID: "V_VP8"},
+		}
+		doc.Segment.Cluster = []cluster{
+			{
+				Timecode: 0,
+				SimpleBlock: []Block{
+					{TrackNumber: 1, Timecode: 0, Keyframe: true, Data: [][]byte{{0x01, 0x02}}},
+					{TrackNumber: 1, Timecode: 10, Data: [][]byte{{0x03}}},
+				},
+			},
+		}
+		return doc
+	}
+	marshal := func(t *testing.T, doc interface{}) []byte {
+		var buf bytes.Buffer
+		if err := Marshal(doc, &buf); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		return buf.Bytes()
+	}
+	summary := func(doc *document, track uint64) string {
+		s := &diffBlockSummaries{byTrack: make(map[uint64]*blockSummaryBuilder)}
+		for _, c := range doc.Segment.Cluster {
+			for i := range c.SimpleBlock {
+				s.add(&c.SimpleBlock[i], int64(c.Timecode), 0)
+			}
+		}
+		n := &diffNode{}
+		s.appendTo(n)
+		for _, c := range n.children {
+			if c.value.(BlockSummary).TrackNumber == track {
+				return fmt.Sprint(c.value)
+			}
+		}
+		return "<nil>"
+	}
+
+	base := newDoc()
+
+	modified := newDoc()
+	modified.Segment.Info.DateUTC = time.Unix(1700000000, 0)
+	modified.Segment.Tracks.TrackEntry[0].TrackUID = 20
+	modified.Segment.Tracks.TrackEntry = append(modified.Segment.Tracks.TrackEntry,
+		trackEntry{TrackNumber: 2, TrackUID: 30, CodecID: "A_OPUS"},
+	)
+	modified.Segment.Cluster[0].SimpleBlock[1].Data = [][]byte{{0x04}}
+
+	removedCluster := newDoc()
+	removedCluster.Segment.Cluster = nil
+
+	unknownSize := &documentUnknownSize{}
+	unknownSize.Segment.Info = base.Segment.Info
+	unknownSize.Segment.Tracks = base.Segment.Tracks
+	unknownSize.Segment.Cluster = base.Segment.Cluster
+
+	unknownSizeModified := &documentUnknownSize{}
+	unknownSizeModified.Segment.Info = base.Segment.Info
+	unknownSizeModified.Segment.Tracks = base.Segment.Tracks
+	unknownSizeModified.Segment.Cluster = modified.Segment.Cluster
+
+	testCases := map[string]struct {
+		a, b     interface{}
+		opts     []DiffOption
+		expected []string
+	}{
+		"Same": {
+			a: base, b: newDoc(),
+		},
+		"UnknownSize": {
+			a: base, b: unknownSize,
+		},
+		"Modified": {
+			a: base, b: modified,
+			expected: []string{
+				"changed Segment/Info/DateUTC " + fmt.Sprint(base.Segment.Info.DateUTC) + " " + fmt.Sprint(modified.Segment.Info.DateUTC),
+				"changed Segment/Tracks/TrackEntry[0]/TrackUID 10 20",
+				"added Segment/Tracks/TrackEntry[1] <nil> <nil>",
+				"changed Segment/Cluster/SimpleBlock[1] " +
+					fmt.Sprint(blockDigest(t, base.Segment.Cluster[0].SimpleBlock[1])) + " " +
+					fmt.Sprint(blockDigest(t, modified.Segment.Cluster[0].SimpleBlock[1])),
+			},
+		},
+		"IgnoreVolatile": {
+			a: base, b: modified,
+			opts: []DiffOption{WithIgnoreVolatile()},
+			expected: []string{
+				"added Segment/Tracks/TrackEntry[1] <nil> <nil>",
+				"changed Segment/Cluster/SimpleBlock[1] " +
+					fmt.Sprint(blockDigest(t, base.Segment.Cluster[0].SimpleBlock[1])) + " " +
+					fmt.Sprint(blockDigest(t, modified.Segment.Cluster[0].SimpleBlock[1])),
+			},
+		},
+		"BlockSummary": {
+			a: base, b: modified,
+			opts: []DiffOption{WithIgnoreVolatile(), WithBlockSummary()},
+			expected: []string{
+				"added Segment/Tracks/TrackEntry[1] <nil> <nil>",
+				"changed Segment/Blocks[track=1] " + summary(base, 1) + " " + summary(modified, 1),
+			},
+		},
+		"IgnoreElements": {
+			a: base, b: modified,
+			opts: []DiffOption{WithIgnoreElements(ElementInfo, ElementTracks, ElementCluster)},
+		},
+		"IgnoreElementsBlockSummary": {
+			a: base, b: modified,
+			opts: []DiffOption{
+				WithIgnoreElements(ElementInfo, ElementTracks, ElementCluster),
+				WithBlockSummary(),
+			},
+		},
+		"IgnoreElementsBlockSummaryUnknownSize": {
+			a: unknownSize, b: unknownSizeModified,
+			opts: []DiffOption{WithIgnoreElements(ElementCluster), WithBlockSummary()},
+		},
+		"Removed": {
+			a: base, b: removedCluster,
+			expected: []string{
+				"removed Segment/Cluster <nil> <nil>",
+			},
+		},
+	}
+	for name, c := range testCases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			diffs, err := Diff(
+				bytes.NewReader(marshal(t, c.a)),
+				bytes.NewReader(marshal(t, c.b)),
+				c.opts...,
+			)
+			if err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			var actual []string
+			for _, d := range diffs {
+				actual = append(actual, fmt.Sprintf("%s %s %v %v", d.Kind, d.Path, d.Old, d.New))
+			}
+			if !reflect.DeepEqual(c.expected, actual) {
+				t.Errorf("Unexpected differences\nexpected: %v\n     got: %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDiff_UnknownElement(t *testing.T) {
+	a := []byte{
+		0x18, 0x53, 0x80, 0x67, 0x8F, // Segment
+		0x5F, 0xFF, 0x82, 0x01, 0x02,
+		0x5F, 0xFF, 0x81, 0x03,
+		0x5F, 0xFE, 0x81, 0xAA,
+	}
+	b := []byte{
+		0x18, 0x53, 0x80, 0x67, 0x8F, // Segment
+		0x5F, 0xFF, 0x82, 0x01, 0x03,
+		0x5F, 0xFF, 0x81, 0x03,
+		0x5F, 0xFD, 0x81, 0xBB,
+	}
+	diffs, err := Diff(bytes.NewReader(a), bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	var actual []string
+	for _, d := range diffs {
+		actual = append(actual, fmt.Sprintf("%s %s %v %v", d.Kind, d.Path, formatValue(d.Old), formatValue(d.New)))
+	}
+	expected := []string{
+		"changed Segment/0x5FFF[0] 0x0102 0x0103",
+		"removed Segment/0x5FFE 0xaa <nil>",
+		"added Segment/0x5FFD <nil> 0xbb",
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Unexpected differences\nexpected: %v\n     got: %v", expected, actual)
+	}
+
+	t.Run("UnknownSize", func(t *testing.T) {
+		c := []byte{
+			0x18, 0x53, 0x80, 0x67, 0x84, // Segment
+			0x5F, 0xFF, 0xFF, 0x01,
+		}
+		_, err := Diff(bytes.NewReader(c), bytes.NewReader(a))
+		if !errs.Is(err, ErrUnknownElement) {
+			t.Errorf("Expected error: '%v', got: '%v'", ErrUnknownElement, err)
+		}
+	})
+}
+
+func blockDigest(t *testing.T, b Block) BinaryDigest {
+	var buf bytes.Buffer
+	if err := MarshalBlock(&b, &buf); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	var ret BinaryDigest
+	ret.Size = uint64(buf.Len())
+	ret.SHA256 = sha256.Sum256(buf.Bytes())
+	return ret
+}
+
+func TestDifference_String(t *testing.T) {
+	testCases := map[string]struct {
+		d        Difference
+		expected string
+	}{
+		"Added": {
+			d:        Difference{Kind: DiffAdded, Path: "Segment/Tags", NewPosition: 10},
+			expected: "+ Segment/Tags at 10",
+		},
+		"Removed": {
+			d:        Difference{Kind: DiffRemoved, Path: "Segment/Info/Title", Old: "a", OldPosition: 12},
+			expected: "- Segment/Info/Title at 12: a",
+		},
+		"Changed": {
+			d: Difference{
+				Kind: DiffChanged, Path: "Segment/Info/SegmentUID",
+				Old: []byte{0x01, 0x02}, New: []byte{0x03},
+				OldPosition: 5, NewPosition: 6,
+			},
+			expected: "~ Segment/Info/SegmentUID at 5/6: 0x0102 -> 0x03",
+		},
+	}
+	for name, c := range testCases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			if s := c.d.String(); s != c.expected {
+				t.Errorf("Expected '%s', got '%s'", c.expected, s)
+			}
+		})
+	}
+}
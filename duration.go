@@ -0,0 +1,127 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"math"
+	"reflect"
+	"time"
+)
+
+// DefaultTimecodeScale is the TimecodeScale used if the Segment doesn't have it.
+const DefaultTimecodeScale = 1000000
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// segmentScaledElements are the elements stored in the unit of Segment's TimecodeScale.
+var segmentScaledElements = map[ElementType]bool{
+	ElementDuration:       true,
+	ElementTimestamp:      true,
+	ElementBlockDuration:  true,
+	ElementReferenceBlock: true,
+	ElementCueTime:        true,
+	ElementCueDuration:    true,
+	ElementCueRefTime:     true,
+}
+
+// durationScale returns the nanoseconds per unit of the element.
+func durationScale(tag *structTag, e ElementType, timecodeScale uint64) uint64 {
+	switch {
+	case tag != nil && tag.scale != 0:
+		return tag.scale
+	case tag != nil && tag.segmentScale, segmentScaledElements[e]:
+		if timecodeScale == 0 {
+			return DefaultTimecodeScale
+		}
+		return timecodeScale
+	}
+	return 1
+}
+
+// isDurationDest returns true if v is time.Duration or a slice of it.
+func isDurationDest(v reflect.Value) bool {
+	if !v.IsValid() {
+		return false
+	}
+	t := v.Type()
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t == durationType
+}
+
+// decodeDuration converts the decoded value in the given scale to time.Duration.
+// ErrOutOfRange is returned if the scaled value overflows time.Duration.
+func decodeDuration(val interface{}, scale uint64) (time.Duration, error) {
+	newOverflowError := func() error {
+		return wrapErrorf(ErrOutOfRange, "unmarshalling %v in scale of %d to time.Duration", val, scale)
+	}
+	switch v := val.(type) {
+	case uint64:
+		if scale != 0 && v > math.MaxInt64/scale {
+			return 0, newOverflowError()
+		}
+		return time.Duration(v * scale), nil
+	case int64:
+		if scale > math.MaxInt64 {
+			if v != 0 {
+				return 0, newOverflowError()
+			}
+			return 0, nil
+		}
+		s := int64(scale)
+		if s != 0 && (v > math.MaxInt64/s || v < math.MinInt64/s) {
+			return 0, newOverflowError()
+		}
+		return time.Duration(v * s), nil
+	case float64:
+		d := math.Round(v * float64(scale))
+		if math.IsNaN(d) || d >= math.MaxInt64 || d < math.MinInt64 {
+			return 0, newOverflowError()
+		}
+		return time.Duration(d), nil
+	}
+	return 0, wrapErrorf(ErrIncompatibleType, "unmarshalling %T to time.Duration", val)
+}
+
+// encodeDuration converts time.Duration to the value of the element in the given scale.
+// The value is rounded to the nearest integer for integer elements.
+// ErrOutOfRange is returned if the value can't be converted without overflow.
+func encodeDuration(d time.Duration, t DataType, scale uint64) (interface{}, error) {
+	switch t {
+	case DataTypeUInt:
+		if d < 0 {
+			return nil, wrapErrorf(ErrOutOfRange, "writing negative duration %v as uint", d)
+		}
+		return (uint64(d) + scale/2) / scale, nil
+	case DataTypeInt:
+		if scale > math.MaxInt64 || d == math.MinInt64 {
+			return nil, wrapErrorf(ErrOutOfRange, "writing %v in scale of %d as int", d, scale)
+		}
+		// Round half away from zero without overflowing int64.
+		s := int64(scale)
+		v, r := int64(d)/s, int64(d)%s
+		switch {
+		case r > 0 && r >= s-r:
+			v++
+		case r < 0 && -r >= s+r:
+			v--
+		}
+		return v, nil
+	case DataTypeFloat:
+		return float64(d) / float64(scale), nil
+	}
+	return nil, wrapErrorf(ErrInvalidType, "writing time.Duration as %s", t)
+}
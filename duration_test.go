@@ -0,0 +1,250 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/at-wat/ebml-go/internal/errs"
+)
+
+func TestDuration(t *testing.T) {
+	type info struct {
+		TimecodeScale uint64        `ebml:"TimecodeScale"`
+		Duration      time.Duration `ebml:"Duration"`
+	}
+	type trackEntry struct {
+		DefaultDuration time.Duration `ebml:"DefaultDuration"`
+		CodecDelay      time.Duration `ebml:"CodecDelay,scale=1000"`
+		SeekPreRoll     time.Duration `ebml:"SeekPreRoll,scale=segment"`
+	}
+	type blockGroup struct {
+		BlockDuration  time.Duration   `ebml:"BlockDuration"`
+		ReferenceBlock []time.Duration `ebml:"ReferenceBlock"`
+	}
+	type cluster struct {
+		Timecode   time.Duration `ebml:"Timecode"`
+		BlockGroup blockGroup    `ebml:"BlockGroup"`
+	}
+	type segment struct {
+		Info   info `ebml:"Info"`
+		Tracks struct {
+			TrackEntry trackEntry `ebml:"TrackEntry"`
+		} `ebml:"Tracks"`
+		Cluster cluster `ebml:"Cluster"`
+	}
+	type document struct {
+		Segment segment `ebml:"Segment"`
+	}
+
+	testCases := map[string]struct {
+		input    document
+		expected []byte
+	}{
+		"DefaultScale": {
+			input: document{Segment: segment{
+				Info: info{TimecodeScale: 1000000, Duration: 1500 * time.Millisecond},
+				Tracks: struct {
+					TrackEntry trackEntry `ebml:"TrackEntry"`
+				}{trackEntry{
+					DefaultDuration: 20 * time.Millisecond,
+					CodecDelay:      6500 * time.Microsecond,
+					SeekPreRoll:     80 * time.Millisecond,
+				}},
+				Cluster: cluster{
+					Timecode: 2 * time.Second,
+					BlockGroup: blockGroup{
+						BlockDuration:  20 * time.Millisecond,
+						ReferenceBlock: []time.Duration{-40 * time.Millisecond},
+					},
+				},
+			}},
+			expected: []byte{
+				0x18, 0x53, 0x80, 0x67, 0xC7,
+				0x15, 0x49, 0xA9, 0x66, 0x92,
+				0x2A, 0xD7, 0xB1, 0x83, 0x0F, 0x42, 0x40, // TimecodeScale = 1000000
+				0x44, 0x89, 0x88, 0x40, 0x97, 0x70, 0x00, 0x00, 0x00, 0x00, 0x00, // Duration = 1500.0
+				0x16, 0x54, 0xAE, 0x6B, 0x93,
+				0xAE, 0x91,
+				0x23, 0xE3, 0x83, 0x84, 0x01, 0x31, 0x2D, 0x00, // DefaultDuration = 20000000
+				0x56, 0xAA, 0x82, 0x19, 0x64, // CodecDelay = 6500
+				0x56, 0xBB, 0x81, 0x50, // SeekPreRoll = 80
+				0x1F, 0x43, 0xB6, 0x75, 0x93,
+				0xE7, 0x82, 0x07, 0xD0, // Timecode = 2000
+				0xA0, 0x8D,
+				0x9B, 0x81, 0x14, // BlockDuration = 20
+				0xFB, 0x88, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xD8, // ReferenceBlock = -40
+			},
+		},
+		"CustomScale": {
+			input: document{Segment: segment{
+				Info: info{TimecodeScale: 10000000, Duration: 1500 * time.Millisecond},
+				Tracks: struct {
+					TrackEntry trackEntry `ebml:"TrackEntry"`
+				}{trackEntry{
+					DefaultDuration: 20 * time.Millisecond,
+					CodecDelay:      6500 * time.Microsecond,
+					SeekPreRoll:     80 * time.Millisecond,
+				}},
+				Cluster: cluster{
+					Timecode: 2 * time.Second,
+					BlockGroup: blockGroup{
+						BlockDuration:  20 * time.Millisecond,
+						ReferenceBlock: []time.Duration{-40 * time.Millisecond},
+					},
+				},
+			}},
+			expected: []byte{
+				0x18, 0x53, 0x80, 0x67, 0xC6,
+				0x15, 0x49, 0xA9, 0x66, 0x92,
+				0x2A, 0xD7, 0xB1, 0x83, 0x98, 0x96, 0x80, // TimecodeScale = 10000000
+				0x44, 0x89, 0x88, 0x40, 0x62, 0xC0, 0x00, 0x00, 0x00, 0x00, 0x00, // Duration = 150.0
+				0x16, 0x54, 0xAE, 0x6B, 0x93,
+				0xAE, 0x91,
+				0x23, 0xE3, 0x83, 0x84, 0x01, 0x31, 0x2D, 0x00, // DefaultDuration = 20000000
+				0x56, 0xAA, 0x82, 0x19, 0x64, // CodecDelay = 6500
+				0x56, 0xBB, 0x81, 0x08, // SeekPreRoll = 8
+				0x1F, 0x43, 0xB6, 0x75, 0x92,
+				0xE7, 0x81, 0xC8, // Timecode = 200
+				0xA0, 0x8D,
+				0x9B, 0x81, 0x02, // BlockDuration = 2
+				0xFB, 0x88, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFC, // ReferenceBlock = -4
+			},
+		},
+	}
+	for name, c := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Marshal(&c.input, &buf); err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			if !bytes.Equal(c.expected, buf.Bytes()) {
+				t.Fatalf("Marshaled binary doesn't match:\n expected: %v,\n      got: %v", c.expected, buf.Bytes())
+			}
+			var ret document
+			if err := Unmarshal(bytes.NewReader(c.expected), &ret); err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			if !reflect.DeepEqual(c.input, ret) {
+				t.Errorf("Unmarshaled value doesn't match:\n expected: %+v,\n      got: %+v", c.input, ret)
+			}
+		})
+	}
+}
+
+func TestEncodeDuration_Int(t *testing.T) {
+	testCases := map[string]struct {
+		d        time.Duration
+		scale    uint64
+		expected int64
+	}{
+		"RoundUp":      {15, 10, 2},
+		"RoundDown":    {14, 10, 1},
+		"NegativeUp":   {-14, 10, -1},
+		"NegativeDown": {-15, 10, -2},
+		"MaxInt64":     {math.MaxInt64, 1000000, 9223372036855},
+		"MinInt64+1":   {math.MinInt64 + 1, 1000000, -9223372036855},
+	}
+	for name, c := range testCases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			v, err := encodeDuration(c.d, DataTypeInt, c.scale)
+			if err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			if v != c.expected {
+				t.Errorf("Expected %d, got %v", c.expected, v)
+			}
+		})
+	}
+}
+
+func TestDuration_Error(t *testing.T) {
+	t.Run("NegativeUInt", func(t *testing.T) {
+		input := struct {
+			DefaultDuration time.Duration `ebml:"DefaultDuration"`
+		}{-1}
+		if err := Marshal(&input, &bytes.Buffer{}); !errs.Is(err, ErrOutOfRange) {
+			t.Errorf("Expected error: '%v', got: '%v'", ErrOutOfRange, err)
+		}
+	})
+	t.Run("Overflow", func(t *testing.T) {
+		testCases := map[string][]byte{
+			"UInt": {
+				0x23, 0xE3, 0x83, 0x88, // DefaultDuration
+				0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			"ScaledUInt": {
+				0x2A, 0xD7, 0xB1, 0x84, 0x00, 0x0F, 0x42, 0x40, // TimecodeScale = 1000000
+				0x9B, 0x88, // BlockDuration
+				0x00, 0x00, 0x08, 0x63, 0x7B, 0xD0, 0x5A, 0xF7,
+			},
+			"ScaledInt": {
+				0xFB, 0x88, // ReferenceBlock
+				0xFF, 0xF0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			"Float": {
+				0x44, 0x89, 0x88, // Duration = 1e13
+				0x42, 0xA2, 0x30, 0x9C, 0xE5, 0x40, 0x00, 0x00,
+			},
+		}
+		for name, b := range testCases {
+			b := b
+			t.Run(name, func(t *testing.T) {
+				var ret struct {
+					TimecodeScale   uint64        `ebml:"TimecodeScale"`
+					DefaultDuration time.Duration `ebml:"DefaultDuration"`
+					BlockDuration   time.Duration `ebml:"BlockDuration"`
+					ReferenceBlock  time.Duration `ebml:"ReferenceBlock"`
+					Duration        time.Duration `ebml:"Duration"`
+				}
+				if err := Unmarshal(bytes.NewReader(b), &ret); !errs.Is(err, ErrOutOfRange) {
+					t.Errorf("Expected error: '%v', got: '%v'", ErrOutOfRange, err)
+				}
+			})
+		}
+	})
+	t.Run("EncodeOverflow", func(t *testing.T) {
+		testCases := map[string]interface{}{
+			"MinInt64": &struct {
+				ReferenceBlock time.Duration `ebml:"ReferenceBlock"`
+			}{math.MinInt64},
+			"LargeScale": &struct {
+				ReferenceBlock time.Duration `ebml:"ReferenceBlock,scale=9223372036854775808"`
+			}{1},
+		}
+		for name, input := range testCases {
+			input := input
+			t.Run(name, func(t *testing.T) {
+				if err := Marshal(input, &bytes.Buffer{}); !errs.Is(err, ErrOutOfRange) {
+					t.Errorf("Expected error: '%v', got: '%v'", ErrOutOfRange, err)
+				}
+			})
+		}
+	})
+	t.Run("String", func(t *testing.T) {
+		var ret struct {
+			DocType time.Duration `ebml:"EBMLDocType"`
+		}
+		b := []byte{0x42, 0x82, 0x81, 0x61}
+		if err := Unmarshal(bytes.NewReader(b), &ret); !errs.Is(err, ErrIncompatibleType) {
+			t.Errorf("Expected error: '%v', got: '%v'", ErrIncompatibleType, err)
+		}
+	})
+}
@@ -39,12 +39,110 @@ func (e *Error) Unwrap() error {
 // Is reports whether chained error contains target.
 // This is for Go1.13 error unwrapping.
 func (e *Error) Is(target error) bool {
-	err := e.Err
+	if target == e {
+		return true
+	}
+	return isChained(e.Err, target)
+}
+
+// ElementError records a failure on the element with its location in the stream.
+//
+// Marshal and Unmarshal return the failures wrapped by ElementError.
+// Note that, this is a breaking change from the older versions which returned
+// the bare errors like io.ErrUnexpectedEOF and ErrUnknownElement.
+// On Go1.13 or later, use errors.Is instead of == to compare the returned error with them.
+// On the older Go versions, call Is method of the returned *ElementError like:
+//
+//	if e, ok := err.(*ebml.ElementError); ok && e.Is(io.ErrUnexpectedEOF) {
+//		// handle unexpected EOF
+//	}
+type ElementError struct {
+	Err error
+	// Path is the slash separated path of the element
+	// like "Segment/Cluster[42]/SimpleBlock[7]".
+	// Index in the brackets is the zero-based number of the element in the parent.
+	// It is omitted if the destination is not a slice or a channel and the element is the first one.
+	Path string
+	// Position is the absolute offset of the element header in the stream.
+	// It is not set on Marshal since the offset is not fixed until
+	// the data size fields of the parent elements are written.
+	Position uint64
+	// ID is the element ID. Zero if the ID is not read.
+	ID uint32
+	// Type is the type of the element. ElementInvalid if the element is unknown.
+	Type ElementType
+	// Expected is the type of the destination value.
+	// Expected and Actual are set only if the element value is not compatible with the destination.
+	Expected reflect.Type
+	// Actual is the type of the decoded value.
+	Actual reflect.Type
+
+	// unlocated is true if Position is not set.
+	unlocated bool
+}
+
+func (e *ElementError) Error() string {
+	msg := e.Path
+	if msg == "" {
+		msg = "(root)"
+	}
+	if e.ID != 0 {
+		msg += fmt.Sprintf(" (0x%X)", e.ID)
+	}
+	if e.unlocated {
+		return fmt.Sprintf("%s: %s", msg, e.Err.Error())
+	}
+	return fmt.Sprintf("%s at %d: %s", msg, e.Position, e.Err.Error())
+}
+
+// Unwrap returns the reason of the failure.
+// This is for Go1.13 error unwrapping.
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
 
-	switch target {
-	case e:
+// Is reports whether chained error contains target.
+// This is for Go1.13 error unwrapping.
+func (e *ElementError) Is(target error) bool {
+	if target == e {
 		return true
-	case nil:
+	}
+	return isChained(e.Err, target)
+}
+
+// withParent prepends the path of the parent element to err if err is ElementError.
+func withParent(err error, parent string) error {
+	if e, ok := err.(*ElementError); ok {
+		if e.Path == "" {
+			e.Path = parent
+		} else {
+			e.Path = parent + "/" + e.Path
+		}
+	}
+	return err
+}
+
+// elementPathName returns the name of the element used in ElementError.Path.
+func elementPathName(t ElementType, index int, repeated bool) string {
+	if !repeated && index == 0 {
+		return t.String()
+	}
+	return fmt.Sprintf("%s[%d]", t, index)
+}
+
+// isRepeatedDest returns true if v can store multiple elements.
+func isRepeatedDest(v reflect.Value, binary bool) bool {
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func:
+		return true
+	case reflect.Slice:
+		return !(binary && v.Type().Elem().Kind() == reflect.Uint8)
+	}
+	return false
+}
+
+func isChained(err, target error) bool {
+	if target == nil {
 		return err == nil
 	}
 	for {
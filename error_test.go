@@ -85,3 +85,37 @@ func TestError(t *testing.T) {
 		t.Errorf("Unwrapped error expected: %s, got: %s", errBase, errChained.(*Error).Unwrap())
 	}
 }
+
+func TestElementError(t *testing.T) {
+	errBase := errors.New("an error")
+	err := withParent(withParent(&ElementError{
+		Err:      wrapErrorf(errBase, "info"),
+		Path:     "SimpleBlock[7]",
+		Position: 1234,
+		ID:       0xA3,
+		Type:     ElementSimpleBlock,
+	}, "Cluster[42]"), "Segment")
+
+	if !errs.Is(err, errBase) {
+		t.Errorf("Wrapped error '%v' doesn't chain '%v'", err, errBase)
+	}
+	if !err.(*ElementError).Is(err) {
+		t.Errorf("Wrapped error '%v' doesn't match its-self", err)
+	}
+	if err.(*ElementError).Is(errors.New("an error")) {
+		t.Errorf("Wrapped error '%v' unexpectedly matched another error", err)
+	}
+	if _, ok := err.(*ElementError).Unwrap().(*Error); !ok {
+		t.Errorf("Unwrapped error expected to be *Error, got: %T", err.(*ElementError).Unwrap())
+	}
+
+	errStr := "Segment/Cluster[42]/SimpleBlock[7] (0xA3) at 1234: info: an error"
+	if err.Error() != errStr {
+		t.Errorf("Error string expected: %s, got: %s", errStr, err.Error())
+	}
+
+	errNotElement := withParent(errBase, "Segment")
+	if errNotElement != errBase {
+		t.Errorf("Non ElementError must not be modified, got: %v", errNotElement)
+	}
+}
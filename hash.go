@@ -0,0 +1,32 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"hash"
+)
+
+// Hash writes val to h in the canonical form and returns the hash value.
+// h is not reset before writing.
+//
+// The result doesn't depend on the encoding like data size length and
+// Void elements, so it can be used to deduplicate or sign the documents
+// by the contents.
+func Hash(val interface{}, h hash.Hash) ([]byte, error) {
+	if err := Marshal(val, h, WithCanonical()); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	type trackEntry struct {
+		Name        string
+		TrackNumber uint64
+	}
+	type trackEntryPadded struct {
+		TrackNumber uint64 `ebml:"TrackNumber,size=8"`
+		Void        []byte
+		Name        string `ebml:"Name,size=16"`
+	}
+	a := struct {
+		Tracks struct {
+			TrackEntry []trackEntry
+		}
+	}{}
+	a.Tracks.TrackEntry = []trackEntry{{Name: "Video", TrackNumber: 1}}
+
+	b := struct {
+		Tracks struct {
+			TrackEntry []trackEntryPadded
+		} `ebml:"Tracks,size=unknown"`
+	}{}
+	b.Tracks.TrackEntry = []trackEntryPadded{{TrackNumber: 1, Void: []byte{0, 0}, Name: "Video"}}
+
+	c := a
+	c.Tracks.TrackEntry = []trackEntry{{Name: "Audio", TrackNumber: 1}}
+
+	var bufA, bufB bytes.Buffer
+	if err := Marshal(&a, &bufA); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if err := Marshal(&b, &bufB); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if bytes.Equal(bufA.Bytes(), bufB.Bytes()) {
+		t.Fatal("Test documents are expected to have different encoding")
+	}
+
+	hashA, err := Hash(&a, sha256.New())
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	hashB, err := Hash(&b, sha256.New())
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	hashC, err := Hash(&c, sha256.New())
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if !bytes.Equal(hashA, hashB) {
+		t.Errorf("Expected same hash for same contents, got %x and %x", hashA, hashB)
+	}
+	if bytes.Equal(hashA, hashC) {
+		t.Errorf("Expected different hash for different contents, got %x", hashA)
+	}
+
+	if _, err := Hash(a, sha256.New()); err == nil {
+		t.Error("Expected error for non-pointer value")
+	}
+}
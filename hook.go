@@ -20,12 +20,19 @@ import (
 
 // Element represents an EBML element.
 type Element struct {
-	Value    interface{}
-	Name     string
-	Type     ElementType
+	Value interface{}
+	Name  string
+	Type  ElementType
+	// Position is the absolute offset of the element header (element ID).
 	Position uint64
-	Size     uint64
-	Parent   *Element
+	// Size is the size of the element data.
+	// SizeUnknown is set if the size is unknown.
+	Size uint64
+	// HeaderSize is the total length of the element ID and the data size field.
+	HeaderSize uint64
+	// DataPosition is the absolute offset of the element data.
+	DataPosition uint64
+	Parent       *Element
 }
 
 func withElementMap(m map[string][]*Element) func(*Element) {
@@ -66,7 +66,7 @@ func (l *xiphLacer) Write(b [][]byte) error {
 	size := []byte{byte(nFrames - 1)}
 	for i := 0; i < nFrames-1; i++ {
 		n := len(b[i])
-		for ; n > 0xFF; n -= 0xFF {
+		for ; n >= 0xFF; n -= 0xFF {
 			size = append(size, 0xFF)
 		}
 		size = append(size, byte(n))
@@ -164,3 +164,76 @@ func NewFixedLacer(w io.Writer) Lacer {
 func NewEBMLLacer(w io.Writer) Lacer {
 	return &ebmlLacer{w}
 }
+
+// laceHeaderSize returns the size of the lacing header of the frames.
+func laceHeaderSize(mode LacingMode, b [][]byte) (int, error) {
+	nFrames := len(b)
+	switch {
+	case mode == LacingNo:
+		if nFrames > 1 {
+			return 0, wrapErrorf(ErrTooManyFrames, "lacing %d frames by no-lacer", nFrames)
+		}
+		return 0, nil
+	case nFrames == 0:
+		return 0, nil
+	case nFrames > 0xFF:
+		return 0, wrapErrorf(ErrTooManyFrames, "lacing %d frames", nFrames)
+	}
+	size := 1
+	switch mode {
+	case LacingXiph:
+		for i := 0; i < nFrames-1; i++ {
+			size += len(b[i])/0xFF + 1
+		}
+	case LacingFixed:
+		for i := 1; i < nFrames; i++ {
+			if len(b[i]) != len(b[0]) {
+				return 0, wrapErrorf(
+					ErrUnevenFixedLace, "lacing %d bytes on %d bytes frame", len(b[i]), len(b[0]),
+				)
+			}
+		}
+	case LacingEBML:
+		n, err := encodeElementID(uint64(len(b[0])))
+		if err != nil {
+			return 0, err
+		}
+		size += len(n)
+		for i := 1; i < nFrames-1; i++ {
+			n, err := encodeVInt(int64(len(b[i])) - int64(len(b[i-1])))
+			if err != nil {
+				return 0, err
+			}
+			size += len(n)
+		}
+	default:
+		return 0, wrapErrorf(ErrInvalidType, "lacing mode %d", mode)
+	}
+	return size, nil
+}
+
+// selectLacing returns the lacing mode with the smallest lacing header
+// and the header size.
+// Fixed lacing is preferred over EBML lacing, and EBML lacing over Xiph lacing
+// if the sizes are same.
+func selectLacing(b [][]byte) (LacingMode, int, error) {
+	if len(b) <= 1 {
+		return LacingNo, 0, nil
+	}
+	best, bestSize := LacingNo, -1
+	var err error
+	for _, mode := range []LacingMode{LacingFixed, LacingEBML, LacingXiph} {
+		var size int
+		size, err = laceHeaderSize(mode, b)
+		if err != nil {
+			continue
+		}
+		if bestSize < 0 || size < bestSize {
+			best, bestSize = mode, size
+		}
+	}
+	if bestSize < 0 {
+		return LacingNo, 0, err
+	}
+	return best, bestSize, nil
+}
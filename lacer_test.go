@@ -61,6 +61,23 @@ func TestLacer(t *testing.T) {
 						bytes.Repeat([]byte{0x55}, 8)}, []byte{})...),
 			err: nil,
 		},
+		"XiphMultipleOf255": {
+			newLacer: NewXiphLacer,
+			frames: [][]byte{
+				bytes.Repeat([]byte{0xAA}, 255),
+				bytes.Repeat([]byte{0xCC}, 1),
+			},
+			b: append(
+				[]byte{
+					0x01,
+					0xFF, 0x00, // 255 bytes
+				},
+				bytes.Join(
+					[][]byte{
+						bytes.Repeat([]byte{0xAA}, 255),
+						bytes.Repeat([]byte{0xCC}, 1)}, []byte{})...),
+			err: nil,
+		},
 		"XiphEmpty": {
 			newLacer: NewXiphLacer,
 			frames:   [][]byte{},
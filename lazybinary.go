@@ -0,0 +1,154 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+)
+
+// LazyBinary is a binary element data read from the source on demand.
+//
+// Binary element can be unmarshalled to LazyBinary, *LazyBinary,
+// *io.SectionReader and io.Reader without reading the data,
+// if the source io.Reader implements io.ReaderAt and io.Seeker.
+// Otherwise, the data is read to the memory.
+type LazyBinary struct {
+	*io.SectionReader
+	// Offset is the offset of the data in the source stream.
+	Offset int64
+}
+
+// NewLazyBinary creates LazyBinary reading n bytes from r at offset off.
+func NewLazyBinary(r io.ReaderAt, off, n int64) *LazyBinary {
+	return &LazyBinary{
+		SectionReader: io.NewSectionReader(r, off, n),
+		Offset:        off,
+	}
+}
+
+// Bytes reads whole data.
+func (b *LazyBinary) Bytes() ([]byte, error) {
+	buf := make([]byte, b.Size())
+	if _, err := b.ReadAt(buf, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return buf, nil
+}
+
+var (
+	lazyBinaryType       = reflect.TypeOf(LazyBinary{})
+	lazyBinaryPtrType    = reflect.TypeOf(&LazyBinary{})
+	sectionReaderPtrType = reflect.TypeOf(&io.SectionReader{})
+	readerType           = reflect.TypeOf((*io.Reader)(nil)).Elem()
+)
+
+func isLazyBinaryType(t reflect.Type) bool {
+	switch t {
+	case lazyBinaryType, lazyBinaryPtrType, sectionReaderPtrType, readerType:
+		return true
+	}
+	return false
+}
+
+// lazyBinaryDest returns the type of the lazy binary destination.
+// nil is returned if v is not a lazy binary or a slice of it.
+func lazyBinaryDest(v reflect.Value) reflect.Type {
+	if !v.IsValid() {
+		return nil
+	}
+	t := v.Type()
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if isLazyBinaryType(t) {
+		return t
+	}
+	return nil
+}
+
+// lazyBinaryValue converts LazyBinary to the value of type t.
+func lazyBinaryValue(b *LazyBinary, t reflect.Type) reflect.Value {
+	switch t {
+	case lazyBinaryType:
+		return reflect.ValueOf(*b)
+	case lazyBinaryPtrType:
+		return reflect.ValueOf(b)
+	case sectionReaderPtrType:
+		return reflect.ValueOf(b.SectionReader)
+	default:
+		v := reflect.New(t).Elem()
+		v.Set(reflect.ValueOf(b.SectionReader))
+		return v
+	}
+}
+
+// readLazyBinary skips the binary data and returns LazyBinary referring the source.
+// pos is the position of the data in the stream.
+func (d *valueDecoder) readLazyBinary(r io.Reader, pos, n uint64) (*LazyBinary, error) {
+	off := d.offset + int64(pos)
+	if d.readerAt == nil {
+		bs, err := readSlice(r, n)
+		switch err {
+		case nil:
+		case io.EOF:
+			return nil, io.ErrUnexpectedEOF
+		default:
+			return nil, err
+		}
+		return &LazyBinary{
+			SectionReader: io.NewSectionReader(bytes.NewReader(bs), 0, int64(n)),
+			Offset:        off,
+		}, nil
+	}
+	if err := skip(r, n); err != nil {
+		return nil, err
+	}
+	return NewLazyBinary(d.readerAt, off, int64(n)), nil
+}
+
+type sizedReaderAt interface {
+	io.ReaderAt
+	Size() int64
+}
+
+type lenReader interface {
+	io.Reader
+	Len() int
+}
+
+// binarySource returns io.Reader and the length of the binary
+// if v is a reader with known length.
+// Reader with Len() is read from the current position,
+// and io.ReaderAt with Size() is read from offset 0.
+func binarySource(v reflect.Value) (io.Reader, int64, bool) {
+	if !v.IsValid() {
+		return nil, 0, false
+	}
+	var i interface{}
+	if v.CanAddr() {
+		i = v.Addr().Interface()
+	} else {
+		i = v.Interface()
+	}
+	switch r := i.(type) {
+	case lenReader:
+		return r, int64(r.Len()), true
+	case sizedReaderAt:
+		return io.NewSectionReader(r, 0, r.Size()), r.Size(), true
+	}
+	return nil, 0, false
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/at-wat/ebml-go/internal/errs"
+)
+
+func TestUnmarshal_LazyBinary(t *testing.T) {
+	b := []byte{
+		0x00, 0x00, // leading bytes before the stream
+		0x1A, 0x45, 0xDF, 0xA3, 0x90,
+		0x53, 0xAB, 0x83, 0x01, 0x02, 0x03, // SeekID = 0x010203
+		0x53, 0xAB, 0x82, 0x04, 0x05, // SeekID = 0x0405
+		0x53, 0xAB, 0x81, 0x06, // SeekID = 0x06
+	}
+	type result struct {
+		Header struct {
+			SeekID []LazyBinary `ebml:"SeekID"`
+		} `ebml:"EBML"`
+	}
+	check := func(t *testing.T, ret *result, lazy bool) {
+		expected := []struct {
+			data   []byte
+			offset int64
+		}{
+			{[]byte{0x01, 0x02, 0x03}, 10},
+			{[]byte{0x04, 0x05}, 16},
+			{[]byte{0x06}, 21},
+		}
+		if n := len(ret.Header.SeekID); n != len(expected) {
+			t.Fatalf("Expected %d elements, got %d", len(expected), n)
+		}
+		for i, e := range expected {
+			lb := ret.Header.SeekID[i]
+			if lb.Offset != e.offset {
+				t.Errorf("Expected offset of element %d: %d, got: %d", i, e.offset, lb.Offset)
+			}
+			data, err := lb.Bytes()
+			if err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			if !bytes.Equal(e.data, data) {
+				t.Errorf("Expected data of element %d: %v, got: %v", i, e.data, data)
+			}
+		}
+		if lazy {
+			// Data must be read from the source on demand.
+			b[10] = 0xFF
+			data, err := ret.Header.SeekID[0].Bytes()
+			if err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			if data[0] != 0xFF {
+				t.Error("Binary is not read lazily")
+			}
+			b[10] = 0x01
+		}
+	}
+
+	t.Run("ReadSeeker", func(t *testing.T) {
+		r := bytes.NewReader(b)
+		if _, err := r.Seek(2, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		var ret result
+		if err := Unmarshal(r, &ret); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		check(t, &ret, true)
+	})
+	t.Run("UnmarshalBytes", func(t *testing.T) {
+		var ret result
+		if err := UnmarshalBytes(b[2:], &ret); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		for i := range ret.Header.SeekID {
+			ret.Header.SeekID[i].Offset += 2
+		}
+		check(t, &ret, true)
+	})
+	t.Run("Reader", func(t *testing.T) {
+		var ret result
+		if err := Unmarshal(&readerOnly{bytes.NewReader(b[2:])}, &ret); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		for i := range ret.Header.SeekID {
+			ret.Header.SeekID[i].Offset += 2
+		}
+		check(t, &ret, false)
+	})
+}
+
+type readerOnly struct {
+	r io.Reader
+}
+
+func (r *readerOnly) Read(b []byte) (int, error) {
+	return r.r.Read(b)
+}
+
+func TestUnmarshal_LazyBinaryTypes(t *testing.T) {
+	b := []byte{
+		0x1A, 0x45, 0xDF, 0xA3, 0x84,
+		0x53, 0xAB, 0x81, 0x01, // SeekID = 0x01
+	}
+	var ret struct {
+		Header struct {
+			Ptr *LazyBinary `ebml:"SeekID"`
+		} `ebml:"EBML"`
+	}
+	var ret2 struct {
+		Header struct {
+			Reader io.Reader `ebml:"SeekID"`
+		} `ebml:"EBML"`
+	}
+	var ret3 struct {
+		Header struct {
+			Section *io.SectionReader `ebml:"SeekID"`
+		} `ebml:"EBML"`
+	}
+	for _, v := range []interface{}{&ret, &ret2, &ret3} {
+		if err := UnmarshalBytes(b, v); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+	}
+	readers := map[string]io.Reader{
+		"*LazyBinary":       ret.Header.Ptr,
+		"io.Reader":         ret2.Header.Reader,
+		"*io.SectionReader": ret3.Header.Section,
+	}
+	for name, r := range readers {
+		t.Run(name, func(t *testing.T) {
+			data, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			if !bytes.Equal([]byte{0x01}, data) {
+				t.Errorf("Expected data: [1], got: %v", data)
+			}
+		})
+	}
+}
+
+func TestMarshal_LazyBinary(t *testing.T) {
+	expected := []byte{
+		0x1A, 0x45, 0xDF, 0xA3, 0x8B,
+		0x53, 0xAB, 0x82, 0x01, 0x02, // SeekID = 0x0102
+		0x53, 0xAB, 0x83, 0x03, 0x00, 0x00, // SeekID = 0x03, padded
+	}
+	testCases := map[string]func() interface{}{
+		"Reader": func() interface{} {
+			return &struct {
+				Header struct {
+					SeekID  *bytes.Reader `ebml:"SeekID"`
+					SeekID2 io.Reader     `ebml:"SeekID,size=3"`
+				} `ebml:"EBML"`
+			}{
+				Header: struct {
+					SeekID  *bytes.Reader `ebml:"SeekID"`
+					SeekID2 io.Reader     `ebml:"SeekID,size=3"`
+				}{
+					SeekID:  bytes.NewReader([]byte{0x01, 0x02}),
+					SeekID2: bytes.NewReader([]byte{0x03}),
+				},
+			}
+		},
+		"PartiallyReadReader": func() interface{} {
+			src := bytes.NewReader([]byte{0xFF, 0x01, 0x02})
+			_, _ = src.ReadByte()
+			return &struct {
+				Header struct {
+					SeekID  *bytes.Reader `ebml:"SeekID"`
+					SeekID2 []byte        `ebml:"SeekID,size=3"`
+				} `ebml:"EBML"`
+			}{
+				Header: struct {
+					SeekID  *bytes.Reader `ebml:"SeekID"`
+					SeekID2 []byte        `ebml:"SeekID,size=3"`
+				}{
+					SeekID:  src,
+					SeekID2: []byte{0x03},
+				},
+			}
+		},
+		"LazyBinary": func() interface{} {
+			src := bytes.NewReader([]byte{0x00, 0x01, 0x02, 0x03})
+			return &struct {
+				Header struct {
+					SeekID  LazyBinary        `ebml:"SeekID"`
+					SeekID2 *io.SectionReader `ebml:"SeekID,size=3"`
+				} `ebml:"EBML"`
+			}{
+				Header: struct {
+					SeekID  LazyBinary        `ebml:"SeekID"`
+					SeekID2 *io.SectionReader `ebml:"SeekID,size=3"`
+				}{
+					SeekID:  *NewLazyBinary(src, 1, 2),
+					SeekID2: io.NewSectionReader(src, 3, 1),
+				},
+			}
+		},
+	}
+	for name, fn := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Marshal(fn(), &buf); err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			if !bytes.Equal(expected, buf.Bytes()) {
+				t.Errorf("Marshaled binary doesn't match:\n expected: %v,\n      got: %v", expected, buf.Bytes())
+			}
+		})
+	}
+
+	t.Run("ShortReader", func(t *testing.T) {
+		src := bytes.NewReader([]byte{0x00, 0x01})
+		lb := NewLazyBinary(src, 0, 4)
+		s := struct {
+			SeekID *LazyBinary `ebml:"SeekID"`
+		}{SeekID: lb}
+		var buf bytes.Buffer
+		if err := Marshal(&s, &buf); !errs.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Expected error: '%v', got: '%v'", io.ErrUnexpectedEOF, err)
+		}
+	})
+}
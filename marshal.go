@@ -16,9 +16,12 @@ package ebml
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"reflect"
+	"sort"
+	"time"
 )
 
 // ErrUnsupportedElement means that a element name is known but unsupported in this version of ebml-go.
@@ -29,6 +32,18 @@ var ErrNonStringMapKey = errors.New("non-string map key")
 
 // Marshal struct to EBML bytes.
 //
+// Elements in map[string]interface{} are marshalled in the schema order.
+// Use OrderedMap to marshal elements in an arbitrary order.
+//
+// Binary element can be marshalled from io.Reader with known length,
+// which has Len() int (like *bytes.Reader) or implements io.ReaderAt
+// and Size() int64 (like *io.SectionReader and LazyBinary).
+// Reader with Len() is read from the current position,
+// and other io.ReaderAt is read from offset 0 regardless of the read position.
+// The data is copied without reading it into []byte,
+// but it is buffered in the memory with the other contents
+// if the parent master element has known size.
+//
 // Examples of struct field tags:
 //
 //   // Field appears as element "EBMLVersion".
@@ -54,6 +69,19 @@ var ErrNonStringMapKey = errors.New("non-string map key")
 //   // Field appears as element "EBMLVersion" and
 //   // the size of the element data is reserved by 4 bytes.
 //   Field uint64 `ebml:EBMLVersion,size=4`
+//
+//   // Field appears as element "Duration" in the unit of
+//   // TimecodeScale of the Segment.
+//   Field time.Duration `ebml:Duration`
+//
+//   // Field appears as element "DefaultDuration" in microseconds.
+//   Field time.Duration `ebml:DefaultDuration,scale=1000`
+//
+// time.Duration is stored in nanoseconds by default.
+// Duration, Timestamp, BlockDuration, ReferenceBlock, CueTime, CueDuration
+// and CueRefTime, and the elements tagged by "scale=segment" are stored
+// in the unit of TimecodeScale of the Segment
+// (DefaultTimecodeScale if TimecodeScale is not marshalled before them).
 func Marshal(val interface{}, w io.Writer, opts ...MarshalOption) error {
 	options := &MarshalOptions{}
 	for _, o := range opts {
@@ -61,12 +89,15 @@ func Marshal(val interface{}, w io.Writer, opts ...MarshalOption) error {
 			return err
 		}
 	}
+	if options.canonical {
+		options.dataSizeLen = 0
+	}
 	vo := reflect.ValueOf(val)
 	if vo.Kind() != reflect.Ptr {
 		return wrapErrorf(ErrInvalidType, "marshalling to %T", val)
 	}
 
-	_, err := marshalImpl(vo.Elem(), w, 0, nil, options)
+	_, err := marshalImpl(vo.Elem(), w, 0, nil, options, nil)
 	return err
 }
 
@@ -79,7 +110,7 @@ func pealElem(v reflect.Value, binary, omitEmpty bool) ([]reflect.Value, bool) {
 			}
 			v = v.Elem()
 		case reflect.Slice:
-			if binary {
+			if binary && v.Type().Elem().Kind() == reflect.Uint8 {
 				if omitEmpty && v.Len() == 0 {
 					return nil, false
 				}
@@ -110,64 +141,163 @@ func deepIsZero(v reflect.Value) bool {
 	return reflect.DeepEqual(reflect.Zero(v.Type()).Interface(), v.Interface())
 }
 
-func marshalImpl(vo reflect.Value, w io.Writer, pos uint64, parent *Element, options *MarshalOptions) (uint64, error) {
+// marshalImpl writes elements of vo to w.
+// If pending is not nil, written elements are appended to it instead of
+// calling hooks, since their positions are not fixed until the size of
+// the known-size parent is written.
+func marshalImpl(vo reflect.Value, w io.Writer, pos uint64, parent *Element, options *MarshalOptions, pending *[]*Element) (uint64, error) {
+	emit := func(elem *Element) {
+		if pending != nil {
+			*pending = append(*pending, elem)
+			return
+		}
+		for _, cb := range options.hooks {
+			cb(elem)
+		}
+	}
+
 	var l int
-	var tagFieldFunc func(int) (*structTag, reflect.Value, error)
-
-	switch vo.Kind() {
-	case reflect.Struct:
-		l = vo.NumField()
-		tagFieldFunc = func(i int) (*structTag, reflect.Value, error) {
-			tag := &structTag{}
-			tn := vo.Type().Field(i)
-			if n, ok := tn.Tag.Lookup("ebml"); ok {
-				var err error
-				if tag, err = parseTag(n); err != nil {
-					return nil, reflect.Value{}, err
-				}
-			}
-			if tag.name == "" {
-				tag.name = tn.Name
+	var tagFieldFunc func(int) (*structTag, ElementType, reflect.Value, error)
+
+	switch {
+	case vo.IsValid() && vo.Type() == orderedMapType:
+		items := vo.FieldByName("Items")
+		l = items.Len()
+		tagFieldFunc = func(i int) (*structTag, ElementType, reflect.Value, error) {
+			item := items.Index(i)
+			name := item.FieldByName("Key").String()
+			t, err := ElementTypeFromString(name)
+			if err != nil {
+				return nil, 0, reflect.Value{}, err
 			}
-			return tag, vo.Field(i), nil
+			return &structTag{name: name}, t, item.FieldByName("Value"), nil
+		}
+	case vo.Kind() == reflect.Struct:
+		si, err := getStructInfo(vo.Type())
+		if err != nil {
+			return pos, err
+		}
+		l = len(si.fields)
+		tagFieldFunc = func(i int) (*structTag, ElementType, reflect.Value, error) {
+			f := &si.fields[i]
+			return f.tag, f.t, vo.Field(f.index), nil
 		}
-	case reflect.Map:
+	case vo.Kind() == reflect.Map:
 		l = vo.Len()
 		keys := vo.MapKeys()
-		tagFieldFunc = func(i int) (*structTag, reflect.Value, error) {
+		sortMapKeys(keys)
+		tagFieldFunc = func(i int) (*structTag, ElementType, reflect.Value, error) {
 			name := keys[i]
 			if name.Kind() != reflect.String {
-				return nil, reflect.Value{}, ErrNonStringMapKey
+				return nil, 0, reflect.Value{}, ErrNonStringMapKey
 			}
-			return &structTag{name: name.String()}, vo.MapIndex(name), nil
+			t, err := ElementTypeFromString(name.String())
+			if err != nil {
+				return nil, 0, reflect.Value{}, err
+			}
+			return &structTag{name: name.String()}, t, vo.MapIndex(name), nil
 		}
 	default:
 		return pos, ErrIncompatibleType
 	}
 
-	for i := 0; i < l; i++ {
-		tag, vn, err := tagFieldFunc(i)
-		if err != nil {
-			return pos, err
+	if options.canonical && vo.Kind() != reflect.Map {
+		// Sort the elements in the schema order.
+		// Map keys are always sorted.
+		types := make([]ElementType, l)
+		order := make([]int, l)
+		for i := range order {
+			order[i] = i
+			if _, t, _, err := tagFieldFunc(i); err == nil {
+				types[i] = t
+			}
 		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return compareSchemaOrder(types[order[i]], types[order[j]]) < 0
+		})
+		unsorted := tagFieldFunc
+		tagFieldFunc = func(i int) (*structTag, ElementType, reflect.Value, error) {
+			return unsorted(order[i])
+		}
+	}
 
-		t, err := ElementTypeFromString(tag.name)
+	for i := 0; i < l; i++ {
+		tag, t, vn, err := tagFieldFunc(i)
 		if err != nil {
 			return pos, err
 		}
+
 		e, ok := table[t]
 		if !ok {
-			return pos, wrapErrorf(ErrUnsupportedElement, "marshalling \"%s\"", t)
+			return pos, &ElementError{
+				Err:       wrapErrorf(ErrUnsupportedElement, "marshalling \"%s\"", t),
+				Path:      t.String(),
+				Type:      t,
+				unlocated: true,
+			}
+		}
+
+		if options.canonical && t == ElementVoid {
+			continue
 		}
 
-		unknown := tag.size == SizeUnknown
+		unknown := tag.size == SizeUnknown && !options.canonical
+		// fixedSize is the size of the element data specified by the tag.
+		fixedSize := tag.size
+		if options.canonical {
+			fixedSize = 0
+		}
 
 		lst, ok := pealElem(vn, e.t == DataTypeBinary, tag.omitEmpty)
 		if !ok {
 			continue
 		}
 
+		// writeBinaryFrom copies binary data from io.Reader with known length.
+		writeBinaryFrom := func(src io.Reader, l int64, headerSize uint64) (uint64, error) {
+			size := uint64(l)
+			if fixedSize > size {
+				size = fixedSize
+			}
+			bsz := encodeDataSize(size, options.dataSizeLen)
+			n, err := w.Write(bsz)
+			if err != nil {
+				return pos, err
+			}
+			headerSize += uint64(n)
+
+			switch _, err := io.CopyN(w, src, l); err {
+			case nil:
+			case io.EOF:
+				return pos, io.ErrUnexpectedEOF
+			default:
+				return pos, err
+			}
+			if pad := size - uint64(l); pad > 0 {
+				if _, err := w.Write(make([]byte, pad)); err != nil {
+					return pos, err
+				}
+			}
+			if len(options.hooks) > 0 {
+				emit(&Element{
+					Value:        src,
+					Name:         tag.name,
+					Type:         t,
+					Position:     pos,
+					Size:         size,
+					HeaderSize:   headerSize,
+					DataPosition: pos + headerSize,
+					Parent:       parent,
+				})
+			}
+			pos += headerSize + size
+			return pos, nil
+		}
+
 		writeOne := func(vn reflect.Value) (uint64, error) {
+			if err := contextError(options.ctx); err != nil {
+				return pos, err
+			}
 			// Write element ID
 			var headerSize uint64
 			n, err := w.Write(e.b)
@@ -176,6 +306,12 @@ func marshalImpl(vo reflect.Value, w io.Writer, pos uint64, parent *Element, opt
 			}
 			headerSize += uint64(n)
 
+			if e.t == DataTypeBinary {
+				if src, l, ok := binarySource(vn); ok {
+					return writeBinaryFrom(src, l, headerSize)
+				}
+			}
+
 			var bw io.Writer
 			if unknown {
 				// Directly write length unspecified element
@@ -191,26 +327,49 @@ func marshalImpl(vo reflect.Value, w io.Writer, pos uint64, parent *Element, opt
 			}
 
 			var elem *Element
+			var children []*Element
 			if len(options.hooks) > 0 {
 				elem = &Element{
-					Value:    vn.Interface(),
-					Name:     tag.name,
-					Type:     t,
-					Position: pos,
-					Size:     SizeUnknown,
-					Parent:   parent,
+					Value:        vn.Interface(),
+					Name:         tag.name,
+					Type:         t,
+					Position:     pos,
+					Size:         SizeUnknown,
+					HeaderSize:   headerSize,
+					DataPosition: pos + headerSize,
+					Parent:       parent,
 				}
 			}
 
 			var size uint64
 			if e.t == DataTypeMaster {
-				p, err := marshalImpl(vn, bw, pos+headerSize, elem, options)
+				if t == ElementSegment {
+					options.timecodeScale = 0
+				}
+				childPending := pending
+				if !unknown && elem != nil {
+					childPending = &children
+				}
+				p, err := marshalImpl(vn, bw, pos+headerSize, elem, options, childPending)
 				if err != nil {
 					return pos, err
 				}
 				size = p - pos - headerSize
 			} else {
-				bc, err := perTypeEncoder[e.t](vn.Interface(), tag.size)
+				val := vn.Interface()
+				if d, ok := val.(time.Duration); ok {
+					var err error
+					val, err = encodeDuration(d, e.t, durationScale(tag, t, options.timecodeScale))
+					if err != nil {
+						return pos, err
+					}
+				}
+				if t == ElementTimecodeScale {
+					if ts, ok := val.(uint64); ok {
+						options.timecodeScale = ts
+					}
+				}
+				bc, err := perTypeEncoder[e.t](val, fixedSize)
 				if err != nil {
 					return pos, err
 				}
@@ -223,9 +382,6 @@ func marshalImpl(vo reflect.Value, w io.Writer, pos uint64, parent *Element, opt
 
 			// Write element with length
 			if !unknown {
-				if len(options.hooks) > 0 {
-					elem.Size = size
-				}
 				bsz := encodeDataSize(size, options.dataSizeLen)
 				n, err := w.Write(bsz)
 				if err != nil {
@@ -236,35 +392,79 @@ func marshalImpl(vo reflect.Value, w io.Writer, pos uint64, parent *Element, opt
 				if _, err := w.Write(bw.(*bytes.Buffer).Bytes()); err != nil {
 					return pos, err
 				}
+				if elem != nil {
+					elem.Size = size
+					elem.HeaderSize = headerSize
+					elem.DataPosition = pos + headerSize
+					// Children were marshalled before the data size is known.
+					// Shift them by the length of the data size field.
+					for _, c := range children {
+						c.Position += uint64(n)
+						c.DataPosition += uint64(n)
+					}
+				}
 			}
-			for _, cb := range options.hooks {
-				cb(elem)
+			if elem != nil {
+				for _, c := range children {
+					emit(c)
+				}
+				emit(elem)
 			}
 			pos += headerSize + size
 			return pos, nil
 		}
 
+		repeated := isRepeatedDest(vn, e.t == DataTypeBinary)
+		var index int
+		// write writes an element and locates the error.
+		write := func(vn reflect.Value) error {
+			p, err := writeOne(vn)
+			if isContextError(options.ctx, err) {
+				return err
+			}
+			if err != nil {
+				name := elementPathName(t, index, repeated)
+				if _, ok := err.(*ElementError); ok {
+					return withParent(err, name)
+				}
+				return &ElementError{
+					Err:       err,
+					Path:      name,
+					ID:        elementID(e.b),
+					Type:      t,
+					unlocated: true,
+				}
+			}
+			pos = p
+			index++
+			return nil
+		}
+
 		for _, vn := range lst {
 			var err error
 			switch vn.Kind() {
 			case reflect.Chan:
-				for {
-					val, ok := vn.Recv()
-					if !ok {
+				for err == nil {
+					val, ok, errRecv := recvContext(options.ctx, vn)
+					if errRecv != nil {
+						err = errRecv
 						break
 					}
-					lst, ok := pealElem(val, e.t == DataTypeBinary, tag.omitEmpty)
 					if !ok {
-						return pos, wrapErrorf(
-							ErrIncompatibleType, "marshalling %s from channel", val.Type(),
-						)
+						break
 					}
-					if len(lst) != 1 {
-						return pos, wrapErrorf(
+					lst, ok := pealElem(val, e.t == DataTypeBinary, tag.omitEmpty)
+					if !ok || len(lst) != 1 {
+						err = wrapErrorf(
 							ErrIncompatibleType, "marshalling %s from channel", val.Type(),
 						)
+						break
 					}
-					pos, err = writeOne(lst[0])
+					err = write(lst[0])
+				}
+				if err != nil && contextError(options.ctx) != nil {
+					// Release the producer blocked on sending after ctx is done.
+					drainChan(vn)
 				}
 			case reflect.Func:
 				ret := vn.Call(nil)
@@ -289,10 +489,10 @@ func marshalImpl(vo reflect.Value, w io.Writer, pos uint64, parent *Element, opt
 					)
 				}
 				for _, l := range lst {
-					pos, err = writeOne(l)
+					err = write(l)
 				}
 			default:
-				pos, err = writeOne(vn)
+				err = write(vn)
 			}
 			if err != nil {
 				return pos, err
@@ -302,6 +502,15 @@ func marshalImpl(vo reflect.Value, w io.Writer, pos uint64, parent *Element, opt
 	return pos, nil
 }
 
+// elementID returns the element ID from its binary representation.
+func elementID(b []byte) uint32 {
+	var id uint32
+	for _, c := range b {
+		id = id<<8 | uint32(c)
+	}
+	return id
+}
+
 // MarshalOption configures a MarshalOptions struct.
 type MarshalOption func(*MarshalOptions) error
 
@@ -309,6 +518,13 @@ type MarshalOption func(*MarshalOptions) error
 type MarshalOptions struct {
 	dataSizeLen uint64
 	hooks       []func(elem *Element)
+	canonical   bool
+
+	// timecodeScale is the TimecodeScale of the Segment being marshalled.
+	timecodeScale uint64
+
+	// ctx stops the write if done.
+	ctx context.Context
 }
 
 // WithDataSizeLen returns an MarshalOption which sets number of reserved bytes of element data size.
@@ -319,6 +535,20 @@ func WithDataSizeLen(l int) MarshalOption {
 	}
 }
 
+// WithCanonical returns an MarshalOption which makes Marshal writing
+// the canonical form of the document.
+// Same document is always marshalled to the same bytes in canonical form:
+//   - data sizes are written in the shortest length, ignoring WithDataSizeLen and size tag
+//   - master elements are written with known size
+//   - Void elements are omitted
+//   - elements are written in the schema order, regardless of the struct field order
+func WithCanonical() MarshalOption {
+	return func(opts *MarshalOptions) error {
+		opts.canonical = true
+		return nil
+	}
+}
+
 // WithElementWriteHooks returns an MarshalOption which registers element hooks.
 func WithElementWriteHooks(hooks ...func(*Element)) MarshalOption {
 	return func(opts *MarshalOptions) error {
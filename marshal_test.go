@@ -60,6 +60,12 @@ func TestMarshal(t *testing.T) {
 		DocType        interface{} `ebml:"EBMLDocType"`
 		DocTypeVersion interface{} `ebml:"EBMLDocTypeVersion"`
 	}
+	type TestByteArray struct {
+		SeekID  [2]byte   `ebml:"SeekID"`
+		SeekIDs [][1]byte `ebml:"SeekID"`
+		Omitted [2]byte   `ebml:"SeekID,omitempty"`
+		Sized   [1]byte   `ebml:"SeekID,size=2"`
+	}
 	type TestBlocks struct {
 		Block Block `ebml:"SimpleBlock"`
 	}
@@ -110,6 +116,37 @@ func TestMarshal(t *testing.T) {
 				},
 			},
 		},
+		"ByteArray": {
+			&struct{ EBML TestByteArray }{TestByteArray{
+				SeekID:  [2]byte{0x01, 0x02},
+				SeekIDs: [][1]byte{{0x03}, {0x04}},
+				Sized:   [1]byte{0x05},
+			}},
+			[][]byte{
+				{
+					0x1A, 0x45, 0xDF, 0xA3, 0x92,
+					0x53, 0xAB, 0x82, 0x01, 0x02,
+					0x53, 0xAB, 0x81, 0x03,
+					0x53, 0xAB, 0x81, 0x04,
+					0x53, 0xAB, 0x82, 0x05, 0x00,
+				},
+			},
+		},
+		"MetaFieldsIgnored": {
+			&struct {
+				EBML struct {
+					Offset  int64  `ebml:",offset"`
+					Raw     []byte `ebml:",raw"`
+					DocType string `ebml:"EBMLDocType"`
+				}
+			}{},
+			[][]byte{
+				{
+					0x1A, 0x45, 0xDF, 0xA3, 0x83,
+					0x42, 0x82, 0x80,
+				},
+			},
+		},
 		"Sized": {
 			&struct{ EBML TestSized }{TestSized{"a", 1, 0.0, 0.0, []byte{0x01}}},
 			[][]byte{
@@ -183,10 +220,42 @@ func TestMarshal(t *testing.T) {
 					0x4D, 0x80, 0x84, 0x74, 0x65, 0x73, 0x74,
 					0x57, 0x41, 0x84, 0x61, 0x62, 0x63, 0x64,
 				},
-				{ // Go map element order is unstable
-					0x15, 0x49, 0xA9, 0x66, 0x8e,
+			},
+		},
+		"MapSchemaOrder": {
+			&map[string]interface{}{
+				"Segment": map[string]interface{}{
+					"Cluster": map[string]interface{}{},
+					"Cues":    map[string]interface{}{},
+					"Tracks":  map[string]interface{}{},
+					"Info":    map[string]interface{}{},
+				},
+				"EBML": map[string]interface{}{},
+			},
+			[][]byte{
+				{
+					0x1A, 0x45, 0xDF, 0xA3, 0x80,
+					0x18, 0x53, 0x80, 0x67, 0x94,
+					0x15, 0x49, 0xA9, 0x66, 0x80,
+					0x16, 0x54, 0xAE, 0x6B, 0x80,
+					0x1F, 0x43, 0xB6, 0x75, 0x80,
+					0x1C, 0x53, 0xBB, 0x6B, 0x80,
+				},
+			},
+		},
+		"OrderedMap": {
+			&OrderedMap{
+				Items: []OrderedMapItem{
+					{Key: "WritingApp", Value: "abcd"},
+					{Key: "MuxingApp", Value: "test"},
+					{Key: "WritingApp", Value: "efgh"},
+				},
+			},
+			[][]byte{
+				{
 					0x57, 0x41, 0x84, 0x61, 0x62, 0x63, 0x64,
 					0x4D, 0x80, 0x84, 0x74, 0x65, 0x73, 0x74,
+					0x57, 0x41, 0x84, 0x65, 0x66, 0x67, 0x68,
 				},
 			},
 		},
@@ -341,7 +410,7 @@ func TestMarshal_WithWriteHooks(t *testing.T) {
 
 	expected := map[string][]uint64{
 		"EBML":                     {0},
-		"EBML.EBMLDocTypeVersion":  {4},
+		"EBML.EBMLDocTypeVersion":  {5},
 		"Segment":                  {9},
 		"Segment.Cluster":          {21, 36},
 		"Segment.Cluster.Timecode": {33, 48},
@@ -365,6 +434,19 @@ func TestMarshal_WithWriteHooks(t *testing.T) {
 	case v != 0:
 		t.Errorf("The value should be 0, got %d", v)
 	}
+
+	expectedHeader := map[string]struct{ headerSize, dataPos uint64 }{
+		"EBML":                    {5, 5},
+		"EBML.EBMLDocTypeVersion": {3, 8},
+		"Segment":                 {12, 21},
+	}
+	for key, exp := range expectedHeader {
+		e := m[key][0]
+		if e.HeaderSize != exp.headerSize || e.DataPosition != exp.dataPos {
+			t.Errorf("Unexpected header of %s, expected: (size %d, data %d), got: (size %d, data %d)",
+				key, exp.headerSize, exp.dataPos, e.HeaderSize, e.DataPosition)
+		}
+	}
 }
 
 func ExampleMarshal() {
@@ -713,3 +795,85 @@ func BenchmarkMarshal(b *testing.B) {
 		}
 	}
 }
+
+func TestMarshal_ElementError(t *testing.T) {
+	type cluster struct {
+		Timecode interface{} `ebml:"Timecode"`
+	}
+	input := struct {
+		Segment struct {
+			Cluster []cluster `ebml:"Cluster"`
+		} `ebml:"Segment,size=unknown"`
+	}{}
+	input.Segment.Cluster = []cluster{{uint64(1)}, {"a"}}
+
+	err := Marshal(&input, &bytes.Buffer{})
+	if !errs.Is(err, ErrInvalidType) {
+		t.Fatalf("Expected error: '%v', got: '%v'", ErrInvalidType, err)
+	}
+	elemErr, ok := err.(*ElementError)
+	if !ok {
+		t.Fatalf("Expected *ElementError, got: %T", err)
+	}
+	if elemErr.Path != "Segment/Cluster[1]/Timestamp" {
+		t.Errorf("Expected path: Segment/Cluster[1]/Timestamp, got: %s", elemErr.Path)
+	}
+	if elemErr.ID != 0xE7 || elemErr.Type != ElementTimecode {
+		t.Errorf("Expected element Timecode (0xE7), got: %s (0x%X)", elemErr.Type, elemErr.ID)
+	}
+	if elemErr.Position != 0 {
+		t.Errorf("Position must not be set on Marshal, got: %d", elemErr.Position)
+	}
+	errStr := "Segment/Cluster[1]/Timestamp (0xE7): writing string as uint: invalid type"
+	if err.Error() != errStr {
+		t.Errorf("Error string expected: %s, got: %s", errStr, err.Error())
+	}
+}
+
+func TestMarshal_Canonical(t *testing.T) {
+	input := struct {
+		Segment struct {
+			Tracks struct {
+				TrackEntry struct {
+					TrackNumber uint64 `ebml:"TrackNumber,size=4"`
+				}
+			}
+			Void []byte
+			Info struct {
+				TimecodeScale uint64
+			}
+		} `ebml:"Segment,size=unknown"`
+	}{}
+	input.Segment.Tracks.TrackEntry.TrackNumber = 1
+	input.Segment.Void = make([]byte, 4)
+	input.Segment.Info.TimecodeScale = 1000000
+
+	expected := []byte{
+		0x18, 0x53, 0x80, 0x67, 0x96,
+		0x15, 0x49, 0xA9, 0x66, 0x87,
+		0x2A, 0xD7, 0xB1, 0x83, 0x0F, 0x42, 0x40,
+		0x16, 0x54, 0xAE, 0x6B, 0x85,
+		0xAE, 0x83,
+		0xD7, 0x81, 0x01,
+	}
+
+	var buf bytes.Buffer
+	if err := Marshal(&input, &buf, WithCanonical(), WithDataSizeLen(4)); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if !bytes.Equal(expected, buf.Bytes()) {
+		t.Errorf("Marshaled binary doesn't match:\n expected: %v,\n      got: %v", expected, buf.Bytes())
+	}
+
+	var m map[string]interface{}
+	if err := Unmarshal(bytes.NewReader(buf.Bytes()), &m); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	var buf2 bytes.Buffer
+	if err := Marshal(&m, &buf2, WithCanonical()); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if !bytes.Equal(expected, buf2.Bytes()) {
+		t.Errorf("Marshaled binary from map doesn't match:\n expected: %v,\n      got: %v", expected, buf2.Bytes())
+	}
+}
@@ -19,6 +19,8 @@ package mkv
 
 import (
 	"time"
+
+	"github.com/at-wat/ebml-go"
 )
 
 // EBMLHeader represents EBML header struct.
@@ -34,9 +36,12 @@ type EBMLHeader struct {
 
 // Info represents Info element struct.
 type Info struct {
-	TimecodeScale uint64    `ebml:"TimecodeScale"`
-	MuxingApp     string    `ebml:"MuxingApp,omitempty"`
-	WritingApp    string    `ebml:"WritingApp,omitempty"`
-	Duration      float64   `ebml:"Duration,omitempty"`
-	DateUTC       time.Time `ebml:"DateUTC,omitempty"`
+	SegmentUID    ebml.UID128 `ebml:"SegmentUID,omitempty"`
+	PrevUID       ebml.UID128 `ebml:"PrevUID,omitempty"`
+	NextUID       ebml.UID128 `ebml:"NextUID,omitempty"`
+	TimecodeScale uint64      `ebml:"TimecodeScale"`
+	MuxingApp     string      `ebml:"MuxingApp,omitempty"`
+	WritingApp    string      `ebml:"WritingApp,omitempty"`
+	Duration      float64     `ebml:"Duration,omitempty"`
+	DateUTC       time.Time   `ebml:"DateUTC,omitempty"`
 }
@@ -34,6 +34,14 @@ func (r *blockReader) Read() (b []byte, keyframe bool, timestamp int64, err erro
 	return frame.b, frame.keyframe, frame.timestamp, nil
 }
 
+func (r *blockReader) ReadFrame() (Frame, error) {
+	frame, ok := <-r.f
+	if !ok {
+		return Frame{}, io.EOF
+	}
+	return frame.Frame(), nil
+}
+
 func (r *blockReader) Close() error {
 	close(r.closed)
 	return nil
@@ -44,11 +52,14 @@ func (r *blockReader) TrackEntry() TrackEntry {
 }
 
 // NewSimpleBlockReader creates BlockReadCloserWithTrackEntry for each track specified as tracks argument.
-// It reads SimpleBlock-s and BlockGroup.Block-s. Any optional data in BlockGroup are dropped.
+// It reads SimpleBlock-s and BlockGroup.Block-s.
+// BlockAdditions, BlockDuration, ReferenceBlock, ReferencePriority and DiscardPadding
+// in BlockGroup are available through FrameReader interface. Other optional data in BlockGroup are dropped.
 // If you need full data, consider implementing a custom reader using ebml.Unmarshal.
-//
-// Note that, keyframe flag from BlockGroup.Block may be incorrect.
-// If you have knowledge about this, please consider fixing it.
+// Timestamps and durations of the laced frames are calculated from DefaultDuration of the track,
+// or BlockDuration divided by the number of the frames if DefaultDuration is not set.
+// The index of the frame in the laced block is available through FrameReader interface.
+// BlockGroup.Block is treated as a keyframe if the BlockGroup has no ReferenceBlock.
 func NewSimpleBlockReader(r io.Reader, opts ...BlockReaderOption) ([]BlockReadCloserWithTrackEntry, error) {
 	options := &BlockReaderOptions{
 		BlockReadWriterOptions: BlockReadWriterOptions{
@@ -63,19 +74,32 @@ func NewSimpleBlockReader(r io.Reader, opts ...BlockReaderOption) ([]BlockReadCl
 		}
 	}
 
+	d, err := ebml.NewDecoder(r, options.unmarshalOpts...)
+	if err != nil {
+		return nil, err
+	}
+
 	var header struct {
 		Segment struct {
+			Info struct {
+				TimecodeScale uint64
+			}
 			Tracks struct {
 				TrackEntry []TrackEntry
 			} `ebml:"Tracks,stop"`
 		}
 	}
-	switch err := ebml.Unmarshal(r, &header, options.unmarshalOpts...); err {
+	switch err := d.Decode(&header); err {
 	case ebml.ErrReadStopped:
 	default:
 		return nil, err
 	}
 
+	timecodeScale := header.Segment.Info.TimecodeScale
+	if timecodeScale == 0 {
+		timecodeScale = ebml.DefaultTimecodeScale
+	}
+
 	var ws []BlockReadCloserWithTrackEntry
 	br := make(map[uint64]*blockReader)
 
@@ -91,30 +115,49 @@ func NewSimpleBlockReader(r io.Reader, opts ...BlockReaderOption) ([]BlockReadCl
 
 	type blockGroup struct {
 		Block             ebml.Block
+		BlockAdditions    blockAdditions
+		BlockDuration     uint64
 		ReferencePriority uint64
+		ReferenceBlock    []int64
+		DiscardPadding    int64
 	}
 	type clusterReader struct {
-		Timecode    uint64
+		// Timecode is received through the channel to keep the order
+		// with the blocks since the decoder goes ahead.
+		Timecode    chan uint64
 		SimpleBlock chan ebml.Block
 		BlockGroup  chan blockGroup
 	}
+	timecodeCh := make(chan uint64)
 	blockCh := make(chan ebml.Block)
 	blockGroupCh := make(chan blockGroup)
 	c := struct {
 		Cluster clusterReader
 	}{
 		Cluster: clusterReader{
+			Timecode:    timecodeCh,
 			SimpleBlock: blockCh,
 			BlockGroup:  blockGroupCh,
 		},
 	}
 	go func() {
+		timecodeCh := timecodeCh
 		blockCh := blockCh
 		blockGroupCh := blockGroupCh
+		var timecode uint64
 	L_READ:
 		for {
 			var b *ebml.Block
+			var g *blockGroup
 			select {
+			case tc, ok := <-timecodeCh:
+				if !ok {
+					// Keep the timecode of the last Cluster.
+					timecodeCh = nil
+					continue
+				}
+				timecode = tc
+				continue
 			case block, ok := <-blockCh:
 				if !ok {
 					blockCh = nil
@@ -133,17 +176,64 @@ func NewSimpleBlockReader(r io.Reader, opts ...BlockReaderOption) ([]BlockReadCl
 					continue
 				}
 				b = &bg.Block
-				// FIXME: This may be wrong.
-				//        ReferencePriority == 0 means that the frame is not referenced.
-				b.Keyframe = bg.ReferencePriority != 0
+				g = &bg
+				// Block in BlockGroup without ReferenceBlock is a keyframe.
+				b.Keyframe = len(bg.ReferenceBlock) == 0
 			}
 			r := br[b.TrackNumber]
+			base := int64(timecode) + int64(b.Timecode)
+			n := len(b.Data)
+			var blockDuration uint64
+			if g != nil {
+				blockDuration = g.BlockDuration
+			}
+			// Timestamps of the laced frames are derived from DefaultDuration,
+			// or BlockDuration divided by the number of the frames.
+			lacing := trackLacing{
+				defaultDuration: r.trackEntry.DefaultDuration,
+				timecodeScale:   timecodeScale,
+			}
+			offset := func(l int) int64 {
+				if lacing.defaultDuration == 0 {
+					return int64(uint64(l) * blockDuration / uint64(n))
+				}
+				return lacing.offset(l)
+			}
 			for l := range b.Data {
 				frame := &frame{
 					trackNumber: b.TrackNumber,
 					keyframe:    b.Keyframe,
-					timestamp:   int64(c.Cluster.Timecode) + int64(b.Timecode),
+					timestamp:   base + offset(l),
 					b:           b.Data[l],
+					invisible:   b.Invisible,
+					discardable: b.Discardable,
+					laceIndex:   l,
+				}
+				if g != nil {
+					switch {
+					case blockDuration == 0:
+					case n == 1:
+						frame.duration = int64(blockDuration)
+					default:
+						// Duration of the laced frame is derived in the same way as the timestamp.
+						frame.duration = offset(l+1) - offset(l)
+					}
+					frame.referencePriority = g.ReferencePriority
+					frame.discardPadding = g.DiscardPadding
+					for _, ref := range g.ReferenceBlock {
+						frame.references = append(frame.references, base+ref)
+					}
+					for _, m := range g.BlockAdditions.BlockMore {
+						if frame.additions == nil {
+							frame.additions = make(map[uint64][]byte)
+						}
+						id := m.BlockAddID
+						if id == 0 {
+							// BlockAddID defaults to 1.
+							id = 1
+						}
+						frame.additions[id] = m.BlockAdditional
+					}
 				}
 				select {
 				case r.f <- frame:
@@ -157,10 +247,11 @@ func NewSimpleBlockReader(r io.Reader, opts ...BlockReaderOption) ([]BlockReadCl
 	}()
 	go func() {
 		defer func() {
+			close(timecodeCh)
 			close(blockCh)
 			close(blockGroupCh)
 		}()
-		if err := ebml.Unmarshal(r, &c, options.unmarshalOpts...); err != nil {
+		if err := d.Decode(&c); err != nil && err != io.EOF {
 			if options.onFatal != nil {
 				options.onFatal(err)
 			}
@@ -149,7 +149,7 @@ func TestBlockReader(t *testing.T) {
 				},
 				{
 					{keyframe: true, timestamp: 110, b: []byte{0x03, 0x04, 0x05}},
-					{keyframe: false, timestamp: 140, b: []byte{0x07}},
+					{keyframe: true, timestamp: 140, b: []byte{0x07}},
 				},
 			},
 		},
@@ -264,6 +264,123 @@ func readWithTimeout(r BlockReader) error {
 	}
 }
 
+func TestBlockReader_Lacing(t *testing.T) {
+	type testMkvHeader struct {
+		Segment flexSegment `ebml:"Segment"`
+	}
+	laced := ebml.Block{
+		TrackNumber: 1,
+		Timecode:    10,
+		Keyframe:    true,
+		Lacing:      ebml.LacingXiph,
+		Data:        [][]byte{{0x01}, {0x02}, {0x03}},
+	}
+	testCases := map[string]struct {
+		trackEntry map[string]interface{}
+		cluster    simpleBlockCluster
+		expected   []Frame
+	}{
+		"DefaultDuration": {
+			trackEntry: map[string]interface{}{
+				"TrackNumber":     uint(1),
+				"DefaultDuration": uint(20000000),
+			},
+			cluster: simpleBlockCluster{
+				Timecode:    100,
+				SimpleBlock: []ebml.Block{laced},
+			},
+			expected: []Frame{
+				{Keyframe: true, Timestamp: 110, Data: []byte{0x01}, LaceIndex: 0},
+				{Keyframe: true, Timestamp: 130, Data: []byte{0x02}, LaceIndex: 1},
+				{Keyframe: true, Timestamp: 150, Data: []byte{0x03}, LaceIndex: 2},
+			},
+		},
+		"BlockDuration": {
+			trackEntry: map[string]interface{}{
+				"TrackNumber": uint(1),
+			},
+			cluster: simpleBlockCluster{
+				Timecode: 100,
+				BlockGroup: []simpleBlockGroup{
+					{Block: []ebml.Block{laced}, BlockDuration: 100},
+				},
+			},
+			expected: []Frame{
+				{Keyframe: true, Timestamp: 110, Data: []byte{0x01}, Duration: 33, LaceIndex: 0},
+				{Keyframe: true, Timestamp: 143, Data: []byte{0x02}, Duration: 33, LaceIndex: 1},
+				{Keyframe: true, Timestamp: 176, Data: []byte{0x03}, Duration: 34, LaceIndex: 2},
+			},
+		},
+		"DefaultDurationAndBlockDuration": {
+			trackEntry: map[string]interface{}{
+				"TrackNumber":     uint(1),
+				"DefaultDuration": uint(20000000),
+			},
+			cluster: simpleBlockCluster{
+				Timecode: 100,
+				BlockGroup: []simpleBlockGroup{
+					{Block: []ebml.Block{laced}, BlockDuration: 90},
+				},
+			},
+			expected: []Frame{
+				{Keyframe: true, Timestamp: 110, Data: []byte{0x01}, Duration: 20, LaceIndex: 0},
+				{Keyframe: true, Timestamp: 130, Data: []byte{0x02}, Duration: 20, LaceIndex: 1},
+				{Keyframe: true, Timestamp: 150, Data: []byte{0x03}, Duration: 20, LaceIndex: 2},
+			},
+		},
+		"DefaultDurationAndBlockDurationWithoutLacing": {
+			trackEntry: map[string]interface{}{
+				"TrackNumber":     uint(1),
+				"DefaultDuration": uint(20000000),
+			},
+			cluster: simpleBlockCluster{
+				Timecode: 100,
+				BlockGroup: []simpleBlockGroup{
+					{
+						Block:         []ebml.Block{{TrackNumber: 1, Timecode: 10, Data: [][]byte{{0x01}}}},
+						BlockDuration: 90,
+					},
+				},
+			},
+			expected: []Frame{
+				{Keyframe: true, Timestamp: 110, Data: []byte{0x01}, Duration: 90},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			input := testMkvHeader{
+				Segment: flexSegment{
+					Tracks:  flexTracks{TrackEntry: []interface{}{testCase.trackEntry}},
+					Cluster: []simpleBlockCluster{testCase.cluster},
+				},
+			}
+			var buf bytes.Buffer
+			if err := ebml.Marshal(&input, &buf); err != nil {
+				t.Fatalf("Failed to marshal test data: '%v'", err)
+			}
+
+			rs, err := NewSimpleBlockReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to create BlockReader: '%v'", err)
+			}
+			for _, expected := range testCase.expected {
+				f, err := rs[0].(FrameReader).ReadFrame()
+				if err != nil {
+					t.Fatalf("Failed to ReadFrame: '%v'", err)
+				}
+				if !reflect.DeepEqual(expected, f) {
+					t.Errorf("Unexpected frame,\nexpected: %+v\n     got: %+v", expected, f)
+				}
+			}
+			if _, err := rs[0].(FrameReader).ReadFrame(); err != io.EOF {
+				t.Errorf("Expected: EOF, got: %v", err)
+			}
+		})
+	}
+}
+
 func TestBlockReader_Close(t *testing.T) {
 	type testMkvHeader struct {
 		Segment flexSegment `ebml:"Segment"`
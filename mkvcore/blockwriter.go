@@ -20,6 +20,7 @@ import (
 	"errors"
 	"io"
 	"math"
+	"sort"
 	"sync"
 
 	"github.com/at-wat/ebml-go"
@@ -40,6 +41,21 @@ type frame struct {
 	keyframe    bool
 	timestamp   int64
 	b           []byte
+	invisible   bool
+	discardable bool
+
+	duration          int64
+	references        []int64
+	referencePriority uint64
+	discardPadding    int64
+	additions         map[uint64][]byte
+
+	// lace is the list of the frames packed in the laced block.
+	lace      []*frame
+	laceIndex int
+	// lacing is set if the frame is written by the lace buffer.
+	// Duration of the frame is derived from DefaultDuration.
+	lacing *trackLacing
 }
 
 func (w *blockWriter) Write(keyframe bool, timestamp int64, b []byte) (int, error) {
@@ -52,6 +68,14 @@ func (w *blockWriter) Write(keyframe bool, timestamp int64, b []byte) (int, erro
 	return len(b), nil
 }
 
+func (w *blockWriter) WriteFrame(f Frame) (int, error) {
+	if err := validateFrame(f); err != nil {
+		return 0, err
+	}
+	w.f <- newFrame(w.trackNumber, f)
+	return len(f.Data), nil
+}
+
 func (w *blockWriter) Close() error {
 	w.wg.Done()
 
@@ -69,6 +93,8 @@ type TrackDescription struct {
 
 // NewSimpleBlockWriter creates BlockWriteCloser for each track specified as tracks argument.
 // Blocks will be written to the writer as EBML SimpleBlocks.
+// Frames written through FrameWriter interface are written as BlockGroups
+// if they have metadata which can't be stored in SimpleBlocks.
 // Given io.WriteCloser will be closed automatically; don't close it by yourself.
 // Frames written to each track must be sorted by their timestamp.
 func NewSimpleBlockWriter(w0 io.WriteCloser, tracks []TrackDescription, opts ...BlockWriterOption) ([]BlockWriteCloser, error) {
@@ -91,7 +117,11 @@ func NewSimpleBlockWriter(w0 io.WriteCloser, tracks []TrackDescription, opts ...
 
 	// Validate Cues requirements
 	var seeker io.WriteSeeker
-	if options.cuesReservedSize > 0 {
+	if options.cuesReservedSize > 0 && options.cuesAtEnd {
+		return nil, ErrCuesModeConflict
+	}
+	withCues := options.cuesReservedSize > 0 || options.cuesAtEnd
+	if withCues {
 		if !options.seekHead {
 			return nil, ErrCuesRequiresSeekHead
 		}
@@ -101,6 +131,33 @@ func NewSimpleBlockWriter(w0 io.WriteCloser, tracks []TrackDescription, opts ...
 			return nil, ErrCuesRequiresSeeker
 		}
 	}
+	var sizes *sizePatcher
+	if options.knownSize {
+		s, ok := w0.(io.WriteSeeker)
+		if !ok {
+			return nil, ErrKnownSizeRequiresSeeker
+		}
+		sizes = &sizePatcher{seeker: s}
+	}
+
+	lacings := make(map[uint64]*trackLacing)
+	for n, budget := range options.lacing {
+		var found bool
+		for _, t := range tracks {
+			if t.TrackNumber != n {
+				continue
+			}
+			l, err := readTrackLacing(t.TrackEntry, options.segmentInfo, budget)
+			if err != nil {
+				return nil, err
+			}
+			lacings[n] = l
+			found = true
+		}
+		if !found {
+			return nil, ErrInvalidTrackNumber
+		}
+	}
 
 	w := &writerWithSizeCount{w: w0}
 
@@ -117,22 +174,27 @@ func NewSimpleBlockWriter(w0 io.WriteCloser, tracks []TrackDescription, opts ...
 	// When Cues are enabled and segmentInfo supports it, set a placeholder
 	// Duration so the marshaler includes the element in the output.
 	// We'll overwrite it with the real value at finalization.
-	if options.cuesReservedSize > 0 {
+	if withCues {
 		if ds, ok := options.segmentInfo.(durationSettable); ok {
 			ds.SetDuration(math.SmallestNonzeroFloat64)
 		}
 	}
 
-	var durationElementPos uint64
-	var segmentDataStart uint64
+	layout := &seekHeadLayout{}
 	if options.seekHead {
 		var err error
-		segmentDataStart, durationElementPos, err = setSeekHead(&header, options.cuesReservedSize > 0, options.marshalOpts...)
+		layout, err = setSeekHead(&header, withCues, options.marshalOpts...)
 		if err != nil {
 			return nil, err
 		}
 	}
-	if err := ebml.Marshal(&header, w, options.marshalOpts...); err != nil {
+	segmentDataStart := layout.segmentDataStart
+	durationElementPos := layout.durationElementPos
+	if sizes != nil {
+		if err := sizes.marshal(&header, w, options.marshalOpts); err != nil {
+			return nil, err
+		}
+	} else if err := ebml.Marshal(&header, w, options.marshalOpts...); err != nil {
 		return nil, err
 	}
 
@@ -201,6 +263,24 @@ func NewSimpleBlockWriter(w0 io.WriteCloser, tracks []TrackDescription, opts ...
 		tc0 := invalidTimestamp
 		tc1 := invalidTimestamp
 		lastTc := int64(0)
+		endTc := int64(0) // end of the latest frame including its duration
+		clusterBlocks := 0
+		lastTrackTc := make(map[uint64]int64)
+		laceBuffers := make(map[uint64]*laceBuffer)
+		for n, l := range lacings {
+			laceBuffers[n] = &laceBuffer{trackLacing: l}
+		}
+
+		// marshalCluster writes Cluster header and closes the previous Cluster.
+		marshalCluster := func(v interface{}) error {
+			if sizes == nil {
+				return ebml.Marshal(v, w, options.marshalOpts...)
+			}
+			if err := sizes.closeCluster(); err != nil {
+				return err
+			}
+			return sizes.marshal(v, w, options.marshalOpts)
+		}
 
 		// Cues tracking state
 		runningPos := posAfterHeader
@@ -220,11 +300,18 @@ func NewSimpleBlockWriter(w0 io.WriteCloser, tracks []TrackDescription, opts ...
 					PrevSize: uint64(w.Size()),
 				},
 			}
-			if err := ebml.Marshal(&cluster, w, options.marshalOpts...); err != nil {
+			if err := marshalCluster(&cluster); err != nil {
 				if options.onFatal != nil {
 					options.onFatal(err)
 				}
 			}
+			if sizes != nil {
+				if err := sizes.closeCluster(); err != nil {
+					if options.onFatal != nil {
+						options.onFatal(err)
+					}
+				}
+			}
 
 			// Write Cues to the reserved space
 			if options.cuesReservedSize > 0 && len(cuePoints) > 0 {
@@ -235,10 +322,18 @@ func NewSimpleBlockWriter(w0 io.WriteCloser, tracks []TrackDescription, opts ...
 				)
 			}
 
+			// Append Cues after the last Cluster
+			if options.cuesAtEnd {
+				writeCuesAtEnd(
+					w, seeker, cuePoints, options.marshalOpts,
+					layout, options.onFatal,
+				)
+			}
+
 			// Overwrite the placeholder Duration with the real value.
 			// Duration element layout: 2-byte ID (0x44 0x89) + 1-byte VINT (0x88) + 8-byte float64.
-			if durationElementPos > 0 && seeker != nil {
-				duration := float64(lastTc - tc0)
+			if withCues && durationElementPos > 0 {
+				duration := float64(endTc - tc0)
 				var buf [8]byte
 				binary.BigEndian.PutUint64(buf[:], math.Float64bits(duration))
 				if _, err := seeker.Seek(int64(durationElementPos)+3, io.SeekStart); err != nil {
@@ -256,77 +351,187 @@ func NewSimpleBlockWriter(w0 io.WriteCloser, tracks []TrackDescription, opts ...
 				}
 			}
 
+			// Segment size must be written after all children.
+			if sizes != nil {
+				if err := sizes.closeSegment(); err != nil {
+					if options.onFatal != nil {
+						options.onFatal(err)
+					}
+				}
+			}
+
 			w.Close()
 			<-fin // read one data to release blocked Close()
 		}()
 
+		// newCluster returns true if the frame should start a new Cluster.
+		newCluster := func(f *frame) bool {
+			c := ClusterState{
+				Timestamp: tc1,
+				Size:      w.Size(),
+				Blocks:    clusterBlocks,
+			}
+			if tc1 == invalidTimestamp {
+				// First Cluster will start at the first frame.
+				c = ClusterState{Timestamp: tc0}
+			}
+			tc := f.timestamp - c.Timestamp
+			if tc >= 0x7FFF || (f.trackNumber == options.mainTrackNumber && tc >= tNextCluster && f.keyframe) {
+				return true
+			}
+			if tc < 0 {
+				// Don't start a Cluster older than the current one.
+				return false
+			}
+			b := BlockInfo{
+				TrackNumber: f.trackNumber,
+				Keyframe:    f.keyframe,
+				Timestamp:   f.timestamp,
+				Size:        f.size(),
+			}
+			for _, p := range options.clusterPolicies {
+				if p.NewCluster(c, b) {
+					return true
+				}
+			}
+			return false
+		}
+
+		// startCluster creates new Cluster starting at the frame
+		// and returns false on fatal error.
+		startCluster := func(f *frame) bool {
+			tc1 = f.timestamp
+			clusterBlocks = 0
+
+			// Collect CuePoint before Clear
+			if withCues {
+				runningPos += uint64(w.Size())
+				cuePoints = append(cuePoints, cuePoint{
+					CueTime: uint64(tc1 - tc0),
+					CueTrackPositions: []cueTrackPosition{{
+						CueTrack:           f.trackNumber,
+						CueClusterPosition: runningPos - segmentDataStart,
+					}},
+				})
+			}
+
+			cluster := struct {
+				Cluster simpleBlockCluster `ebml:"Cluster,size=unknown"`
+			}{
+				Cluster: simpleBlockCluster{
+					Timecode: uint64(tc1 - tc0),
+					PrevSize: uint64(w.Size()),
+				},
+			}
+			w.Clear()
+			if err := marshalCluster(&cluster); err != nil {
+				if options.onFatal != nil {
+					options.onFatal(err)
+				}
+				return false
+			}
+			return true
+		}
+
+		// write writes the frame and returns false on fatal error.
+		write := func(f *frame) bool {
+			last := f
+			if n := len(f.lace); n > 0 {
+				last = f.lace[n-1]
+			}
+			lastTc = last.timestamp
+			if end := f.end(); end > endTc {
+				endTc = end
+			}
+			if tc1 == invalidTimestamp || f.timestamp-tc1 >= 0x7FFF {
+				if !startCluster(f) {
+					return false
+				}
+			}
+			tc := f.timestamp - tc1
+			if tc <= -0x7FFF {
+				// Ignore too old frame
+				if options.onError != nil {
+					options.onError(ErrIgnoreOldFrame)
+				}
+				return true
+			}
+
+			if err := writeBlock(w, f, int16(tc), lastTrackTc, options.marshalOpts); err != nil {
+				if options.onFatal != nil {
+					options.onFatal(err)
+				}
+				return false
+			}
+			lastTrackTc[f.trackNumber] = last.timestamp
+			clusterBlocks++
+			return true
+		}
+
+		// flushLace writes the pending laced frames of the track.
+		flushLace := func(b *laceBuffer) bool {
+			fs, err := b.flush()
+			if err != nil {
+				if options.onFatal != nil {
+					options.onFatal(err)
+				}
+				return false
+			}
+			for _, f := range fs {
+				if !write(f) {
+					return false
+				}
+			}
+			return true
+		}
+		flushLaces := func() bool {
+			for _, t := range tracks {
+				if b, ok := laceBuffers[t.TrackNumber]; ok {
+					if !flushLace(b) {
+						return false
+					}
+				}
+			}
+			return true
+		}
+
 	L_WRITE:
 		for {
 			select {
 			case <-closed:
+				if !flushLaces() {
+					return
+				}
 				break L_WRITE
 			case f := <-ch:
 				if tc0 == invalidTimestamp {
 					tc0 = f.timestamp
+					endTc = f.timestamp
 				}
-				lastTc = f.timestamp
-				tc := f.timestamp - tc1
-				if tc1 == invalidTimestamp || tc >= 0x7FFF || (f.trackNumber == options.mainTrackNumber && tc >= tNextCluster && f.keyframe) {
-					// Create new Cluster
-					tc1 = f.timestamp
-					tc = 0
-
-					// Collect CuePoint before Clear
-					if options.cuesReservedSize > 0 {
-						runningPos += uint64(w.Size())
-						cuePoints = append(cuePoints, cuePoint{
-							CueTime: uint64(tc1 - tc0),
-							CueTrackPositions: []cueTrackPosition{{
-								CueTrack:           f.trackNumber,
-								CueClusterPosition: runningPos - segmentDataStart,
-							}},
-						})
-					}
-
-					cluster := struct {
-						Cluster simpleBlockCluster `ebml:"Cluster,size=unknown"`
-					}{
-						Cluster: simpleBlockCluster{
-							Timecode: uint64(tc1 - tc0),
-							PrevSize: uint64(w.Size()),
-						},
+				// Cluster policy is evaluated once when the frame arrives.
+				// New Cluster is started here even if the frame is held by the lace buffer
+				// so that the frames of the other tracks arriving later are placed in it.
+				if newCluster(f) {
+					// Laced blocks must be placed in the current Cluster.
+					if !flushLaces() {
+						return
 					}
-					w.Clear()
-					if err := ebml.Marshal(&cluster, w, options.marshalOpts...); err != nil {
-						if options.onFatal != nil {
-							options.onFatal(err)
-						}
+					if !startCluster(f) {
 						return
 					}
 				}
-				if tc <= -0x7FFF {
-					// Ignore too old frame
-					if options.onError != nil {
-						options.onError(ErrIgnoreOldFrame)
+				if b, ok := laceBuffers[f.trackNumber]; ok {
+					if b.add(f) {
+						continue
 					}
-					continue
-				}
-
-				b := struct {
-					Block ebml.Block `ebml:"SimpleBlock"`
-				}{
-					ebml.Block{
-						TrackNumber: f.trackNumber,
-						Timecode:    int16(tc),
-						Keyframe:    f.keyframe,
-						Data:        [][]byte{f.b},
-					},
-				}
-				// Write SimpleBlock to the file
-				if err := ebml.Marshal(&b, w, options.marshalOpts...); err != nil {
-					if options.onFatal != nil {
-						options.onFatal(err)
+					if !flushLace(b) {
+						return
+					}
+					if b.add(f) {
+						continue
 					}
+				}
+				if !write(f) {
 					return
 				}
 			}
@@ -336,11 +541,78 @@ func NewSimpleBlockWriter(w0 io.WriteCloser, tracks []TrackDescription, opts ...
 	return ws, nil
 }
 
+// writeBlock writes the frame as SimpleBlock or BlockGroup.
+// lastTrackTc is the timestamp of the previous frame of each track.
+func writeBlock(w io.Writer, f *frame, tc int16, lastTrackTc map[uint64]int64, marshalOpts []ebml.MarshalOption) error {
+	block := ebml.Block{
+		TrackNumber: f.trackNumber,
+		Timecode:    tc,
+		Keyframe:    f.keyframe,
+		Invisible:   f.invisible,
+		Discardable: f.discardable,
+		Data:        [][]byte{f.b},
+	}
+	if len(f.lace) > 1 {
+		block.Data = nil
+		for _, l := range f.lace {
+			block.Data = append(block.Data, l.b)
+		}
+		block.Lacing = ebml.LacingAuto
+	}
+	if !f.blockGroupRequired() {
+		b := struct {
+			Block ebml.Block `ebml:"SimpleBlock"`
+		}{block}
+		return ebml.Marshal(&b, w, marshalOpts...)
+	}
+
+	// Keyframe and discardable flags are reserved in Block;
+	// non-keyframe is indicated by ReferenceBlock.
+	block.Keyframe = false
+	block.Discardable = false
+	refs := f.references
+	if !f.keyframe && len(refs) == 0 {
+		if t, ok := lastTrackTc[f.trackNumber]; ok {
+			refs = []int64{t}
+		}
+	}
+	g := simpleBlockGroup{
+		Block:             []ebml.Block{block},
+		BlockDuration:     uint64(f.duration),
+		ReferencePriority: f.referencePriority,
+		DiscardPadding:    f.discardPadding,
+	}
+	for _, ref := range refs {
+		g.ReferenceBlock = append(g.ReferenceBlock, ref-f.timestamp)
+	}
+	if len(f.additions) > 0 {
+		g.BlockAdditions = &blockAdditions{}
+		for id, b := range f.additions {
+			g.BlockAdditions.BlockMore = append(g.BlockAdditions.BlockMore,
+				blockMore{BlockAddID: id, BlockAdditional: b},
+			)
+		}
+		sort.Slice(g.BlockAdditions.BlockMore, func(i, j int) bool {
+			return g.BlockAdditions.BlockMore[i].BlockAddID < g.BlockAdditions.BlockMore[j].BlockAddID
+		})
+	}
+	b := struct {
+		BlockGroup simpleBlockGroup `ebml:"BlockGroup"`
+	}{g}
+	return ebml.Marshal(&b, w, marshalOpts...)
+}
+
 // writeVoidElement writes an EBML Void element of exactly totalSize bytes.
-// Always uses 8-byte VINT for simplicity; callers must ensure totalSize >= 9.
+// It uses 8-byte VINT for simplicity if totalSize >= 9,
+// and 1-byte VINT otherwise. Callers must ensure totalSize >= 2.
 func writeVoidElement(w io.Writer, totalSize int) error {
 	buf := make([]byte, totalSize)
 	buf[0] = 0xEC // Void Element ID
+	if totalSize < 9 {
+		buf[1] = 0x80 | byte(totalSize-2)
+		_, err := w.Write(buf)
+		return err
+	}
 	dataSize := uint64(totalSize - 9)
 	buf[1] = 0x01
 	buf[2] = byte(dataSize >> 48)
@@ -354,6 +626,70 @@ func writeVoidElement(w io.Writer, totalSize int) error {
 	return err
 }
 
+// writeCuesAtEnd marshals Cues to the end of the file and rewrites SeekPosition of Cues.
+// If there is no CuePoint, Seek element of Cues is replaced by Void.
+func writeCuesAtEnd(
+	w io.Writer,
+	seeker io.WriteSeeker,
+	cuePoints []cuePoint,
+	marshalOpts []ebml.MarshalOption,
+	layout *seekHeadLayout,
+	onFatal func(error),
+) {
+	fatal := func(err error) {
+		if onFatal != nil {
+			onFatal(err)
+		}
+	}
+
+	if len(cuePoints) == 0 {
+		// Seek of Cues points the first Cluster at this point.
+		// Seek is always replaceable since it has 4-bytes SeekID and 8-bytes SeekPosition.
+		if _, err := seeker.Seek(int64(layout.cuesSeekPos), io.SeekStart); err != nil {
+			fatal(err)
+			return
+		}
+		if err := writeVoidElement(seeker, int(layout.cuesSeekSize)); err != nil {
+			fatal(err)
+			return
+		}
+		if _, err := seeker.Seek(0, io.SeekEnd); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	pos, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		fatal(err)
+		return
+	}
+	cuesData := struct {
+		Cues cues `ebml:"Cues"`
+	}{
+		Cues: cues{CuePoint: cuePoints},
+	}
+	if err := ebml.Marshal(&cuesData, w, marshalOpts...); err != nil {
+		fatal(err)
+		return
+	}
+
+	// SeekPosition is written as 8-bytes unsigned integer.
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(pos)-layout.segmentDataStart)
+	if _, err := seeker.Seek(int64(layout.cuesSeekPositionPos), io.SeekStart); err != nil {
+		fatal(err)
+		return
+	}
+	if _, err := seeker.Write(buf[:]); err != nil {
+		fatal(err)
+		return
+	}
+	if _, err := seeker.Seek(0, io.SeekEnd); err != nil {
+		fatal(err)
+	}
+}
+
 // writeCuesToReserved marshals Cues and writes them into the reserved Void space.
 func writeCuesToReserved(
 	seeker io.WriteSeeker,
@@ -1039,6 +1039,27 @@ func TestWriteVoidElement(t *testing.T) {
 	}
 }
 
+func TestWriteVoidElement_Short(t *testing.T) {
+	for _, size := range []int{2, 5, 8} {
+		var buf bytes.Buffer
+		if err := writeVoidElement(&buf, size); err != nil {
+			t.Fatalf("writeVoidElement(%d) failed: %v", size, err)
+		}
+		if buf.Len() != size {
+			t.Errorf("writeVoidElement(%d): got %d bytes, want %d", size, buf.Len(), size)
+		}
+		var v struct {
+			Void []byte `ebml:"Void"`
+		}
+		if err := ebml.Unmarshal(bytes.NewReader(buf.Bytes()), &v); err != nil {
+			t.Fatalf("writeVoidElement(%d): failed to unmarshal: %v", size, err)
+		}
+		if len(v.Void) != size-2 {
+			t.Errorf("writeVoidElement(%d): got %d bytes of data, want %d", size, len(v.Void), size-2)
+		}
+	}
+}
+
 // durationInfo is a test segmentInfo that implements durationSettable.
 type durationInfo struct {
 	TimecodeScale uint64  `ebml:"TimecodeScale"`
@@ -1092,6 +1113,52 @@ func TestBlockWriter_Duration(t *testing.T) {
 
 	})
 
+	t.Run("FrameDuration", func(t *testing.T) {
+		buf := newSeekableBuffer()
+		ws, err := NewSimpleBlockWriter(
+			buf,
+			[]TrackDescription{{TrackNumber: 1}, {TrackNumber: 2}},
+			WithEBMLHeader(nil),
+			WithSegmentInfo(&durationInfo{TimecodeScale: 1000000}),
+			WithSeekHead(true),
+			WithCues(4096),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create BlockWriter: '%v'", err)
+		}
+
+		// The end of the longest frame is used even if it is not the last frame.
+		for _, f := range []struct {
+			track int
+			frame Frame
+		}{
+			{0, Frame{Keyframe: true, Timestamp: 100, Data: []byte{0x01}, Duration: 20}},
+			{1, Frame{Keyframe: true, Timestamp: 110, Data: []byte{0x02}, Duration: 100}},
+			{0, Frame{Keyframe: true, Timestamp: 120, Data: []byte{0x03}, Duration: 20}},
+		} {
+			if _, err := ws[f.track].(FrameWriter).WriteFrame(f.frame); err != nil {
+				t.Fatalf("Failed to WriteFrame: '%v'", err)
+			}
+		}
+		ws[0].Close()
+		ws[1].Close()
+		<-buf.Closed()
+
+		var result struct {
+			Segment struct {
+				Info struct {
+					Duration float64 `ebml:"Duration"`
+				} `ebml:"Info"`
+			} `ebml:"Segment,size=unknown"`
+		}
+		if err := ebml.Unmarshal(bytes.NewReader(buf.Bytes()), &result); err != nil {
+			t.Fatalf("Failed to Unmarshal: '%v'", err)
+		}
+		if expected := 110.0; result.Segment.Info.Duration != expected {
+			t.Errorf("Expected Duration %v, got %v", expected, result.Segment.Info.Duration)
+		}
+	})
+
 	t.Run("WithoutSettable", func(t *testing.T) {
 		// segmentInfo without SetDuration method — no Duration element should appear
 		buf := newSeekableBuffer()
@@ -1158,3 +1225,819 @@ func TestBlockWriter_Duration(t *testing.T) {
 		}
 	})
 }
+
+func TestBlockWriter_WriteFrame(t *testing.T) {
+	frames := []Frame{
+		{Keyframe: true, Timestamp: 0, Data: []byte{0x01}, Duration: 20},
+		{Keyframe: false, Timestamp: 20, Data: []byte{0x02}},
+		{Keyframe: false, Timestamp: 40, Data: []byte{0x03}, Duration: 20},
+		{Keyframe: false, Timestamp: 60, Data: []byte{0x04}, References: []int64{0, 40}},
+		{Keyframe: true, Timestamp: 80, Data: []byte{0x05}, ReferencePriority: 1, DiscardPadding: 6500000},
+		{Keyframe: true, Timestamp: 100, Data: []byte{0x06}, Invisible: true, Discardable: true},
+		{Keyframe: true, Timestamp: 120, Data: []byte{0x07}, ReferencePriority: 1, Invisible: true, Discardable: true,
+			Additions: map[uint64][]byte{2: {0xBB}, 1: {0xAA}},
+		},
+	}
+	expected := simpleBlockCluster{
+		SimpleBlock: []ebml.Block{
+			{TrackNumber: 1, Timecode: 20, Data: [][]byte{{0x02}}},
+			{TrackNumber: 1, Timecode: 100, Keyframe: true, Invisible: true, Discardable: true, Data: [][]byte{{0x06}}},
+		},
+		BlockGroup: []simpleBlockGroup{
+			{
+				Block:         []ebml.Block{{TrackNumber: 1, Timecode: 0, Data: [][]byte{{0x01}}}},
+				BlockDuration: 20,
+			},
+			{
+				Block:          []ebml.Block{{TrackNumber: 1, Timecode: 40, Data: [][]byte{{0x03}}}},
+				BlockDuration:  20,
+				ReferenceBlock: []int64{-20},
+			},
+			{
+				Block:          []ebml.Block{{TrackNumber: 1, Timecode: 60, Data: [][]byte{{0x04}}}},
+				ReferenceBlock: []int64{-60, -20},
+			},
+			{
+				Block:             []ebml.Block{{TrackNumber: 1, Timecode: 80, Data: [][]byte{{0x05}}}},
+				ReferencePriority: 1,
+				DiscardPadding:    6500000,
+			},
+			{
+				Block: []ebml.Block{{TrackNumber: 1, Timecode: 120, Invisible: true, Data: [][]byte{{0x07}}}},
+				BlockAdditions: &blockAdditions{
+					BlockMore: []blockMore{
+						{BlockAddID: 1, BlockAdditional: []byte{0xAA}},
+						{BlockAddID: 2, BlockAdditional: []byte{0xBB}},
+					},
+				},
+				ReferencePriority: 1,
+			},
+		},
+	}
+	expectedRead := []Frame{
+		frames[0],
+		frames[1],
+		{Keyframe: false, Timestamp: 40, Data: []byte{0x03}, Duration: 20, References: []int64{20}},
+		frames[3],
+		frames[4],
+		frames[5],
+		{Keyframe: true, Timestamp: 120, Data: []byte{0x07}, ReferencePriority: 1, Invisible: true,
+			Additions: map[uint64][]byte{1: {0xAA}, 2: {0xBB}},
+		},
+	}
+
+	trackEntry := TrackEntry{
+		TrackNumber:        1,
+		MaxBlockAdditionID: 2,
+		BlockAdditionMapping: []BlockAdditionMapping{
+			{BlockAddIDValue: 2, BlockAddIDName: "test", BlockAddIDType: 1, BlockAddIDExtraData: []byte{0x01}},
+		},
+	}
+
+	testCases := map[string][]BlockWriterOption{
+		"Direct": {},
+		"Interceptor": {
+			WithBlockInterceptor(MustBlockInterceptor(NewMultiTrackBlockSorter(WithMaxDelayedPackets(2)))),
+		},
+	}
+	for name, opts := range testCases {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			buf := buffercloser.New()
+			ws, err := NewSimpleBlockWriter(buf, []TrackDescription{
+				{TrackNumber: 1, TrackEntry: trackEntry},
+			}, opts...)
+			if err != nil {
+				t.Fatalf("Failed to create BlockWriter: '%v'", err)
+			}
+			fw, ok := ws[0].(FrameWriter)
+			if !ok {
+				t.Fatal("BlockWriter must implement FrameWriter")
+			}
+			for _, f := range frames {
+				if n, err := fw.WriteFrame(f); err != nil {
+					t.Fatalf("Failed to WriteFrame: '%v'", err)
+				} else if n != len(f.Data) {
+					t.Errorf("Expected return value of WriteFrame: %d, got: %d", len(f.Data), n)
+				}
+			}
+			ws[0].Close()
+			<-buf.Closed()
+
+			var result struct {
+				Segment flexSegment `ebml:"Segment,size=unknown"`
+			}
+			if err := ebml.Unmarshal(bytes.NewReader(buf.Bytes()), &result); err != nil {
+				t.Fatalf("Failed to Unmarshal resultant binary: '%v'", err)
+			}
+			if len(result.Segment.Cluster) == 0 {
+				t.Fatal("Cluster not found")
+			}
+			if !reflect.DeepEqual(expected, result.Segment.Cluster[0]) {
+				t.Errorf("Unexpected Cluster,\nexpected: %+v\n     got: %+v", expected, result.Segment.Cluster[0])
+			}
+
+			rs, err := NewSimpleBlockReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to create BlockReader: '%v'", err)
+			}
+			if !reflect.DeepEqual(trackEntry, rs[0].TrackEntry()) {
+				t.Errorf("Unexpected TrackEntry,\nexpected: %+v\n     got: %+v", trackEntry, rs[0].TrackEntry())
+			}
+			fr, ok := rs[0].(FrameReader)
+			if !ok {
+				t.Fatal("BlockReader must implement FrameReader")
+			}
+			for _, e := range expectedRead {
+				f, err := fr.ReadFrame()
+				if err != nil {
+					t.Fatalf("Failed to ReadFrame: '%v'", err)
+				}
+				if !reflect.DeepEqual(e, f) {
+					t.Errorf("Unexpected frame,\nexpected: %+v\n     got: %+v", e, f)
+				}
+			}
+			if _, err := fr.ReadFrame(); err != io.EOF {
+				t.Errorf("Expected: EOF, got: %v", err)
+			}
+		})
+	}
+
+	for name, f := range map[string]Frame{
+		"NegativeDuration": {Duration: -1},
+		"ZeroBlockAddID":   {Additions: map[uint64][]byte{0: {0x01}}},
+	} {
+		f := f
+		t.Run(name, func(t *testing.T) {
+			buf := buffercloser.New()
+			ws, err := NewSimpleBlockWriter(buf, []TrackDescription{{TrackNumber: 1}})
+			if err != nil {
+				t.Fatalf("Failed to create BlockWriter: '%v'", err)
+			}
+			defer ws[0].Close()
+
+			if _, err := ws[0].(FrameWriter).WriteFrame(f); !errs.Is(err, ErrInvalidFrame) {
+				t.Errorf("Expected error: '%v', got: '%v'", ErrInvalidFrame, err)
+			}
+		})
+	}
+}
+
+func TestBlockWriter_WithLacing(t *testing.T) {
+	audio := TrackEntry{TrackNumber: 1, FlagLacing: 1, DefaultDuration: 20000000}
+	video := TrackEntry{TrackNumber: 2}
+
+	type block struct {
+		track     uint64
+		timestamp int64
+		data      [][]byte
+	}
+	testCases := map[string]struct {
+		budget     ebml.LaceBudget
+		expected   []block
+		timestamps []int64
+	}{
+		"MaxDuration": {
+			budget: ebml.LaceBudget{MaxDuration: 60},
+			expected: []block{
+				{2, 10, [][]byte{{0x10}}},
+				{1, 0, [][]byte{{0x01}, {0x02}, {0x03}}},
+				{1, 60, [][]byte{{0x04}, {0x05}}},
+				{1, 200, [][]byte{{0x06}}},
+			},
+			timestamps: []int64{0, 20, 40, 60, 80, 200},
+		},
+		"MaxBytes": {
+			budget: ebml.LaceBudget{MaxBytes: 3},
+			expected: []block{
+				{2, 10, [][]byte{{0x10}}},
+				{1, 0, [][]byte{{0x01}, {0x02}}},
+				{1, 40, [][]byte{{0x03}, {0x04}}},
+				{1, 81, [][]byte{{0x05}}},
+				{1, 200, [][]byte{{0x06}}},
+			},
+			timestamps: []int64{0, 20, 40, 60, 81, 200},
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			buf := buffercloser.New()
+			ws, err := NewSimpleBlockWriter(buf,
+				[]TrackDescription{
+					{TrackNumber: 1, TrackEntry: audio},
+					{TrackNumber: 2, TrackEntry: video},
+				},
+				WithLacing(1, testCase.budget),
+			)
+			if err != nil {
+				t.Fatalf("Failed to create BlockWriter: '%v'", err)
+			}
+			for _, f := range []struct {
+				track     int
+				timestamp int64
+				data      []byte
+			}{
+				{0, 0, []byte{0x01}},
+				{1, 10, []byte{0x10}},
+				{0, 20, []byte{0x02}},
+				{0, 40, []byte{0x03}},
+				{0, 60, []byte{0x04}},
+				{0, 81, []byte{0x05}}, // rounding error is allowed
+				{0, 200, []byte{0x06}},
+			} {
+				if _, err := ws[f.track].Write(true, f.timestamp, f.data); err != nil {
+					t.Fatalf("Failed to Write: '%v'", err)
+				}
+			}
+			ws[0].Close()
+			ws[1].Close()
+			<-buf.Closed()
+
+			var result struct {
+				Segment struct {
+					Cluster []struct {
+						Timecode    uint64
+						SimpleBlock []ebml.Block
+					} `ebml:"Cluster,size=unknown"`
+				} `ebml:"Segment,size=unknown"`
+			}
+			if err := ebml.Unmarshal(bytes.NewReader(buf.Bytes()), &result); err != nil {
+				t.Fatalf("Failed to Unmarshal resultant binary: '%v'", err)
+			}
+			var blocks []block
+			for _, c := range result.Segment.Cluster {
+				for _, b := range c.SimpleBlock {
+					blocks = append(blocks, block{b.TrackNumber, int64(c.Timecode) + int64(b.Timecode), b.Data})
+				}
+			}
+			if !reflect.DeepEqual(testCase.expected, blocks) {
+				t.Errorf("Unexpected blocks,\nexpected: %+v\n     got: %+v", testCase.expected, blocks)
+			}
+
+			rs, err := NewSimpleBlockReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Failed to create BlockReader: '%v'", err)
+			}
+			go func() {
+				for {
+					if _, _, _, err := rs[1].Read(); err != nil {
+						return
+					}
+				}
+			}()
+			for i, expected := range testCase.timestamps {
+				b, _, timestamp, err := rs[0].Read()
+				if err != nil {
+					t.Fatalf("Failed to Read: '%v'", err)
+				}
+				if timestamp != expected {
+					t.Errorf("Expected timestamp of frame %d: %d, got: %d", i, expected, timestamp)
+				}
+				if !bytes.Equal([]byte{byte(i + 1)}, b) {
+					t.Errorf("Unexpected data of frame %d: %v", i, b)
+				}
+			}
+		})
+	}
+
+	t.Run("ReferenceToLastFrame", func(t *testing.T) {
+		buf := buffercloser.New()
+		ws, err := NewSimpleBlockWriter(buf,
+			[]TrackDescription{{TrackNumber: 1, TrackEntry: audio}},
+			WithLacing(1, ebml.LaceBudget{}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create BlockWriter: '%v'", err)
+		}
+		for _, ts := range []int64{0, 20, 40} {
+			if _, err := ws[0].Write(false, ts, []byte{0x01}); err != nil {
+				t.Fatalf("Failed to Write: '%v'", err)
+			}
+		}
+		// BlockGroup refers the last frame of the lace.
+		if _, err := ws[0].(FrameWriter).WriteFrame(Frame{Timestamp: 60, Data: []byte{0x02}, Duration: 20}); err != nil {
+			t.Fatalf("Failed to WriteFrame: '%v'", err)
+		}
+		ws[0].Close()
+		<-buf.Closed()
+
+		var result struct {
+			Segment struct {
+				Cluster []simpleBlockCluster `ebml:"Cluster,size=unknown"`
+			} `ebml:"Segment,size=unknown"`
+		}
+		if err := ebml.Unmarshal(bytes.NewReader(buf.Bytes()), &result); err != nil {
+			t.Fatalf("Failed to Unmarshal: '%v'", err)
+		}
+		var refs []int64
+		for _, c := range result.Segment.Cluster {
+			for _, g := range c.BlockGroup {
+				refs = append(refs, g.ReferenceBlock...)
+			}
+		}
+		if expected := []int64{-20}; !reflect.DeepEqual(expected, refs) {
+			t.Errorf("Expected ReferenceBlock: %v, got: %v", expected, refs)
+		}
+	})
+
+	t.Run("Duration", func(t *testing.T) {
+		buf := newSeekableBuffer()
+		ws, err := NewSimpleBlockWriter(buf,
+			[]TrackDescription{{TrackNumber: 1, TrackEntry: audio}},
+			WithEBMLHeader(nil),
+			WithSegmentInfo(&durationInfo{TimecodeScale: 1000000}),
+			WithSeekHead(true),
+			WithCues(4096),
+			WithLacing(1, ebml.LaceBudget{}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create BlockWriter: '%v'", err)
+		}
+		for _, ts := range []int64{0, 20, 40} {
+			if _, err := ws[0].Write(true, ts, []byte{0x01}); err != nil {
+				t.Fatalf("Failed to Write: '%v'", err)
+			}
+		}
+		ws[0].Close()
+		<-buf.Closed()
+
+		var result struct {
+			Segment struct {
+				Info struct {
+					Duration float64 `ebml:"Duration"`
+				} `ebml:"Info"`
+			} `ebml:"Segment,size=unknown"`
+		}
+		if err := ebml.Unmarshal(bytes.NewReader(buf.Bytes()), &result); err != nil {
+			t.Fatalf("Failed to Unmarshal: '%v'", err)
+		}
+		// The last laced frame lasts for DefaultDuration.
+		if expected := 60.0; result.Segment.Info.Duration != expected {
+			t.Errorf("Expected Duration %v, got %v", expected, result.Segment.Info.Duration)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		for name, c := range map[string]struct {
+			trackEntry interface{}
+			track      uint64
+			err        error
+		}{
+			"FlagLacingZero":    {TrackEntry{TrackNumber: 1, DefaultDuration: 20000000}, 1, ErrLacingDisabled},
+			"NoDefaultDuration": {TrackEntry{TrackNumber: 1, FlagLacing: 1}, 1, ErrLacingRequiresDefaultDuration},
+			"UnknownTrack":      {audio, 2, ErrInvalidTrackNumber},
+			"ZeroTrack":         {audio, 0, ErrInvalidTrackNumber},
+		} {
+			c := c
+			t.Run(name, func(t *testing.T) {
+				_, err := NewSimpleBlockWriter(buffercloser.New(),
+					[]TrackDescription{{TrackNumber: 1, TrackEntry: c.trackEntry}},
+					WithLacing(c.track, ebml.LaceBudget{}),
+				)
+				if !errs.Is(err, c.err) {
+					t.Errorf("Expected error: '%v', got: '%v'", c.err, err)
+				}
+			})
+		}
+	})
+}
+
+func TestBlockWriter_WithCuesAtEnd(t *testing.T) {
+	// Segment header with unknown size: 4-bytes ID and 8-bytes size
+	const segmentDataStart = 12
+
+	t.Run("CuesWritten", func(t *testing.T) {
+		buf := newSeekableBuffer()
+		ws, err := NewSimpleBlockWriter(
+			buf,
+			[]TrackDescription{{TrackNumber: 1}},
+			WithEBMLHeader(nil),
+			WithSegmentInfo(&durationInfo{TimecodeScale: 1000000}),
+			WithSeekHead(true),
+			WithCuesAtEnd(),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create BlockWriter: '%v'", err)
+		}
+
+		// More CuePoints than any fixed reserved space used in the other tests
+		const n = 200
+		for i := 0; i < n; i++ {
+			if _, err := ws[0].Write(true, int64(i)*0x8000, []byte{0x01}); err != nil {
+				t.Fatalf("Failed to Write: '%v'", err)
+			}
+		}
+		ws[0].Close()
+		<-buf.Closed()
+
+		var result struct {
+			Segment cuesTestSegment `ebml:"Segment,size=unknown"`
+		}
+		if err := ebml.Unmarshal(bytes.NewReader(buf.Bytes()), &result); err != nil {
+			t.Fatalf("Failed to Unmarshal: '%v'", err)
+		}
+		if result.Segment.Cues == nil {
+			t.Fatal("Expected Cues to be present, got nil")
+		}
+		if got := len(result.Segment.Cues.CuePoint); got != n {
+			t.Errorf("Expected %d CuePoints, got %d", n, got)
+		}
+
+		var cuesPos uint64
+		for _, seek := range result.Segment.SeekHead.Seek {
+			if bytes.Equal(seek.SeekID, ebml.ElementCues.Bytes()) {
+				cuesPos = seek.SeekPosition
+			}
+		}
+		if cuesPos == 0 {
+			t.Fatal("SeekHead does not contain Cues entry")
+		}
+		b := buf.Bytes()[segmentDataStart+cuesPos:]
+		if !bytes.HasPrefix(b, ebml.ElementCues.Bytes()) {
+			t.Errorf("SeekPosition of Cues must point Cues element, got: %v", b[:4])
+		}
+	})
+
+	t.Run("NoFrames", func(t *testing.T) {
+		buf := newSeekableBuffer()
+		ws, err := NewSimpleBlockWriter(
+			buf,
+			[]TrackDescription{{TrackNumber: 1}},
+			WithEBMLHeader(nil),
+			WithSegmentInfo(&durationInfo{TimecodeScale: 1000000}),
+			WithSeekHead(true),
+			WithCuesAtEnd(),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create BlockWriter: '%v'", err)
+		}
+		ws[0].Close()
+		<-buf.Closed()
+
+		var result struct {
+			Segment cuesTestSegment `ebml:"Segment,size=unknown"`
+		}
+		if err := ebml.Unmarshal(bytes.NewReader(buf.Bytes()), &result); err != nil {
+			t.Fatalf("Failed to Unmarshal: '%v'", err)
+		}
+		if result.Segment.Cues != nil {
+			t.Error("Cues must not be written without CuePoint")
+		}
+		for _, seek := range result.Segment.SeekHead.Seek {
+			if bytes.Equal(seek.SeekID, ebml.ElementCues.Bytes()) {
+				t.Error("Seek entry of Cues must be removed")
+			}
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		for name, c := range map[string]struct {
+			w    io.WriteCloser
+			opts []BlockWriterOption
+			err  error
+		}{
+			"Conflict": {
+				newSeekableBuffer(),
+				[]BlockWriterOption{WithSeekHead(true), WithCues(64), WithCuesAtEnd()},
+				ErrCuesModeConflict,
+			},
+			"NoSeekHead": {
+				newSeekableBuffer(),
+				[]BlockWriterOption{WithCuesAtEnd()},
+				ErrCuesRequiresSeekHead,
+			},
+			"NoSeeker": {
+				buffercloser.New(),
+				[]BlockWriterOption{WithSeekHead(true), WithCuesAtEnd()},
+				ErrCuesRequiresSeeker,
+			},
+		} {
+			c := c
+			t.Run(name, func(t *testing.T) {
+				_, err := NewSimpleBlockWriter(c.w, []TrackDescription{{TrackNumber: 1}}, c.opts...)
+				if !errs.Is(err, c.err) {
+					t.Errorf("Expected error: '%v', got: '%v'", c.err, err)
+				}
+			})
+		}
+	})
+}
+
+func TestBlockWriter_WithKnownSize(t *testing.T) {
+	testCases := map[string][]BlockWriterOption{
+		"KnownSize": {
+			WithKnownSize(),
+		},
+		"KnownSizeWithCues": {
+			WithKnownSize(), WithSeekHead(true), WithCuesAtEnd(),
+		},
+		"KnownSizeWithReservedCues": {
+			WithKnownSize(), WithSeekHead(true), WithCues(256),
+		},
+	}
+	for name, opts := range testCases {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			buf := newSeekableBuffer()
+			opts = append([]BlockWriterOption{
+				WithEBMLHeader(nil),
+				WithSegmentInfo(&durationInfo{TimecodeScale: 1000000}),
+			}, opts...)
+			ws, err := NewSimpleBlockWriter(buf,
+				[]TrackDescription{{TrackNumber: 1, TrackEntry: TrackEntry{TrackNumber: 1}}},
+				opts...,
+			)
+			if err != nil {
+				t.Fatalf("Failed to create BlockWriter: '%v'", err)
+			}
+			for i := 0; i < 6; i++ {
+				if _, err := ws[0].Write(true, int64(i)*0x4000, []byte{0x01, 0x02}); err != nil {
+					t.Fatalf("Failed to Write: '%v'", err)
+				}
+			}
+			ws[0].Close()
+			<-buf.Closed()
+
+			data := buf.Bytes()
+			var elements []*ebml.Element
+			hook := func(e *ebml.Element) {
+				switch e.Type {
+				case ebml.ElementSegment, ebml.ElementCluster:
+					elements = append(elements, e)
+				}
+			}
+			var result struct {
+				Segment struct {
+					Cluster []struct {
+						Timecode    uint64
+						SimpleBlock []ebml.Block
+					}
+				}
+			}
+			if err := ebml.Unmarshal(bytes.NewReader(data), &result, ebml.WithElementReadHooks(hook)); err != nil {
+				t.Fatalf("Failed to Unmarshal: '%v'", err)
+			}
+			if n := len(result.Segment.Cluster); n != 4 {
+				t.Fatalf("Expected 4 Clusters, got %d", n)
+			}
+			var nBlocks int
+			for _, c := range result.Segment.Cluster {
+				nBlocks += len(c.SimpleBlock)
+			}
+			if nBlocks != 6 {
+				t.Errorf("Expected 6 SimpleBlocks, got %d", nBlocks)
+			}
+
+			if n := len(elements); n != 5 {
+				t.Fatalf("Expected Segment and 4 Clusters, got %d elements", n)
+			}
+			var clusterEnd uint64
+			for _, e := range elements {
+				if e.Size == ebml.SizeUnknown {
+					t.Fatalf("%s must have known size", e.Name)
+				}
+				switch e.Type {
+				case ebml.ElementSegment:
+					if end := e.DataPosition + e.Size; end != uint64(len(data)) {
+						t.Errorf("Segment must end at %d, ends at %d", len(data), end)
+					}
+				case ebml.ElementCluster:
+					if clusterEnd != 0 && clusterEnd != e.Position {
+						t.Errorf("Cluster must start at the end of the previous Cluster %d, got %d", clusterEnd, e.Position)
+					}
+					clusterEnd = e.DataPosition + e.Size
+				}
+			}
+		})
+	}
+
+	t.Run("NoSeeker", func(t *testing.T) {
+		_, err := NewSimpleBlockWriter(buffercloser.New(), []TrackDescription{{TrackNumber: 1}}, WithKnownSize())
+		if !errs.Is(err, ErrKnownSizeRequiresSeeker) {
+			t.Errorf("Expected error: '%v', got: '%v'", ErrKnownSizeRequiresSeeker, err)
+		}
+	})
+}
+
+func TestBlockWriter_ClusterPolicy(t *testing.T) {
+	testCases := map[string]struct {
+		opts     []BlockWriterOption
+		expected []uint64
+	}{
+		"Default": {
+			expected: []uint64{0},
+		},
+		"MaxClusterDuration": {
+			opts:     []BlockWriterOption{WithMaxClusterDuration(30)},
+			expected: []uint64{0, 33, 66, 100, 133},
+		},
+		"MaxClusterSize": {
+			opts:     []BlockWriterOption{WithMaxClusterSize(40)},
+			expected: []uint64{0, 33, 66, 100, 133},
+		},
+		"ClusterOnKeyframe": {
+			opts:     []BlockWriterOption{WithClusterOnKeyframe(1)},
+			expected: []uint64{0, 66, 133},
+		},
+		"Custom": {
+			opts: []BlockWriterOption{
+				WithClusterPolicy(ClusterPolicyFunc(func(c ClusterState, b BlockInfo) bool {
+					return c.Blocks >= 3
+				})),
+			},
+			expected: []uint64{0, 40, 100},
+		},
+		"Multiple": {
+			opts: []BlockWriterOption{
+				WithClusterOnKeyframe(1),
+				WithMaxClusterDuration(50),
+			},
+			expected: []uint64{0, 66, 133},
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			buf := buffercloser.New()
+			ws, err := NewSimpleBlockWriter(buf,
+				[]TrackDescription{{TrackNumber: 1}, {TrackNumber: 2}},
+				testCase.opts...,
+			)
+			if err != nil {
+				t.Fatalf("Failed to create BlockWriter: '%v'", err)
+			}
+			for _, f := range []struct {
+				track     int
+				keyframe  bool
+				timestamp int64
+				size      int
+			}{
+				{0, true, 0, 10},
+				{1, true, 10, 4},
+				{0, false, 33, 10},
+				{1, true, 40, 4},
+				{0, true, 66, 10},
+				{1, true, 80, 4},
+				{0, false, 100, 10},
+				{0, true, 133, 10},
+			} {
+				if _, err := ws[f.track].Write(f.keyframe, f.timestamp, make([]byte, f.size)); err != nil {
+					t.Fatalf("Failed to Write: '%v'", err)
+				}
+			}
+			ws[0].Close()
+			ws[1].Close()
+			<-buf.Closed()
+
+			var result struct {
+				Segment struct {
+					Cluster []simpleBlockCluster `ebml:"Cluster,size=unknown"`
+				} `ebml:"Segment,size=unknown"`
+			}
+			if err := ebml.Unmarshal(bytes.NewReader(buf.Bytes()), &result); err != nil {
+				t.Fatalf("Failed to Unmarshal: '%v'", err)
+			}
+			var timecodes []uint64
+			var nBlocks int
+			for _, c := range result.Segment.Cluster {
+				if len(c.SimpleBlock) > 0 {
+					timecodes = append(timecodes, c.Timecode)
+					nBlocks += len(c.SimpleBlock)
+				}
+			}
+			if !reflect.DeepEqual(testCase.expected, timecodes) {
+				t.Errorf("Expected Cluster timecodes: %v, got: %v", testCase.expected, timecodes)
+			}
+			if nBlocks != 8 {
+				t.Errorf("Expected 8 blocks, got %d", nBlocks)
+			}
+		})
+	}
+
+	t.Run("EvaluatedOncePerFrame", func(t *testing.T) {
+		var timestamps []int64
+		policy := ClusterPolicyFunc(func(c ClusterState, b BlockInfo) bool {
+			timestamps = append(timestamps, b.Timestamp)
+			return b.Timestamp == 60
+		})
+		buf := buffercloser.New()
+		ws, err := NewSimpleBlockWriter(buf,
+			[]TrackDescription{{
+				TrackNumber: 1,
+				TrackEntry:  TrackEntry{TrackNumber: 1, FlagLacing: 1, DefaultDuration: 20000000},
+			}},
+			WithLacing(1, ebml.LaceBudget{}),
+			WithClusterPolicy(policy),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create BlockWriter: '%v'", err)
+		}
+		for _, ts := range []int64{0, 20, 40, 60, 80} {
+			if _, err := ws[0].Write(true, ts, []byte{0x01}); err != nil {
+				t.Fatalf("Failed to Write: '%v'", err)
+			}
+		}
+		ws[0].Close()
+		<-buf.Closed()
+
+		expectedTimestamps := []int64{0, 20, 40, 60, 80}
+		if !reflect.DeepEqual(expectedTimestamps, timestamps) {
+			t.Errorf("Expected policy calls for: %v, got: %v", expectedTimestamps, timestamps)
+		}
+
+		var result struct {
+			Segment struct {
+				Cluster []simpleBlockCluster `ebml:"Cluster,size=unknown"`
+			} `ebml:"Segment,size=unknown"`
+		}
+		if err := ebml.Unmarshal(bytes.NewReader(buf.Bytes()), &result); err != nil {
+			t.Fatalf("Failed to Unmarshal: '%v'", err)
+		}
+		var timecodes []uint64
+		for _, c := range result.Segment.Cluster {
+			if len(c.SimpleBlock) > 0 {
+				timecodes = append(timecodes, c.Timecode)
+			}
+		}
+		if expected := []uint64{0, 60}; !reflect.DeepEqual(expected, timecodes) {
+			t.Errorf("Expected Cluster timecodes: %v, got: %v", expected, timecodes)
+		}
+	})
+
+	t.Run("LacedFrameStartsCluster", func(t *testing.T) {
+		policy := ClusterPolicyFunc(func(c ClusterState, b BlockInfo) bool {
+			return b.Timestamp == 60
+		})
+		buf := buffercloser.New()
+		ws, err := NewSimpleBlockWriter(buf,
+			[]TrackDescription{
+				{
+					TrackNumber: 1,
+					TrackEntry:  TrackEntry{TrackNumber: 1, FlagLacing: 1, DefaultDuration: 20000000},
+				},
+				{TrackNumber: 2},
+			},
+			WithLacing(1, ebml.LaceBudget{}),
+			WithClusterPolicy(policy),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create BlockWriter: '%v'", err)
+		}
+		for _, f := range []struct {
+			track     int
+			timestamp int64
+		}{
+			{0, 0},
+			{0, 20},
+			{0, 40},
+			{0, 60},
+			{1, 70},
+			{0, 80},
+		} {
+			if _, err := ws[f.track].Write(true, f.timestamp, []byte{0x01}); err != nil {
+				t.Fatalf("Failed to Write: '%v'", err)
+			}
+		}
+		ws[0].Close()
+		ws[1].Close()
+		<-buf.Closed()
+
+		var result struct {
+			Segment struct {
+				Cluster []simpleBlockCluster `ebml:"Cluster,size=unknown"`
+			} `ebml:"Segment,size=unknown"`
+		}
+		if err := ebml.Unmarshal(bytes.NewReader(buf.Bytes()), &result); err != nil {
+			t.Fatalf("Failed to Unmarshal: '%v'", err)
+		}
+		// Frame of the other track arriving while the lace is pending
+		// must be placed in the new Cluster.
+		type block struct {
+			cluster   uint64
+			track     uint64
+			timestamp int16
+		}
+		var blocks []block
+		for _, c := range result.Segment.Cluster {
+			for _, b := range c.SimpleBlock {
+				blocks = append(blocks, block{c.Timecode, b.TrackNumber, b.Timecode})
+			}
+		}
+		expected := []block{
+			{0, 1, 0},
+			{60, 2, 10},
+			{60, 1, 0},
+		}
+		if !reflect.DeepEqual(expected, blocks) {
+			t.Errorf("Unexpected blocks,\nexpected: %+v\n     got: %+v", expected, blocks)
+		}
+	})
+
+	t.Run("InvalidTrackNumber", func(t *testing.T) {
+		_, err := NewSimpleBlockWriter(buffercloser.New(), []TrackDescription{{TrackNumber: 1}}, WithClusterOnKeyframe(0))
+		if !errs.Is(err, ErrInvalidTrackNumber) {
+			t.Errorf("Expected error: '%v', got: '%v'", ErrInvalidTrackNumber, err)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mkvcore
+
+// ClusterState is the state of the Cluster being written.
+type ClusterState struct {
+	// Timestamp is the timestamp of the Cluster in the scale of timecode.
+	Timestamp int64
+	// Size is the number of bytes written to the Cluster including its header.
+	Size int
+	// Blocks is the number of blocks written to the Cluster.
+	Blocks int
+}
+
+// BlockInfo describes the block to be written.
+type BlockInfo struct {
+	TrackNumber uint64
+	Keyframe    bool
+	// Timestamp is in the scale of timecode.
+	Timestamp int64
+	// Size is the total size of the frame data in the block.
+	Size int
+}
+
+// ClusterPolicy is a interface to decide the boundaries of the Clusters.
+type ClusterPolicy interface {
+	// NewCluster returns true to start a new Cluster before writing the block.
+	// It is called once for each frame passed to the writer.
+	// Frames pending to be laced are not counted in the ClusterState,
+	// and the pending laces are flushed to the current Cluster
+	// before starting a new one.
+	// Before writing the first Cluster, ClusterState has the timestamp
+	// of the first frame and zero Size and Blocks.
+	NewCluster(c ClusterState, b BlockInfo) bool
+}
+
+// ClusterPolicyFunc is a function implementing ClusterPolicy.
+type ClusterPolicyFunc func(c ClusterState, b BlockInfo) bool
+
+// NewCluster implements ClusterPolicy.
+func (f ClusterPolicyFunc) NewCluster(c ClusterState, b BlockInfo) bool {
+	return f(c, b)
+}
+
+func maxClusterDurationPolicy(d int64) ClusterPolicy {
+	return ClusterPolicyFunc(func(c ClusterState, b BlockInfo) bool {
+		return b.Timestamp-c.Timestamp >= d
+	})
+}
+
+func maxClusterSizePolicy(size int) ClusterPolicy {
+	return ClusterPolicyFunc(func(c ClusterState, b BlockInfo) bool {
+		return c.Blocks > 0 && c.Size+b.Size > size
+	})
+}
+
+func clusterOnKeyframePolicy(trackNumber uint64) ClusterPolicy {
+	return ClusterPolicyFunc(func(c ClusterState, b BlockInfo) bool {
+		return c.Blocks > 0 && b.TrackNumber == trackNumber && b.Keyframe
+	})
+}
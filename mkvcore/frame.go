@@ -0,0 +1,162 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mkvcore
+
+import (
+	"errors"
+)
+
+// ErrInvalidFrame means that a frame has invalid metadata.
+var ErrInvalidFrame = errors.New("invalid frame")
+
+// Frame is a frame with the block metadata.
+// Frames with any of Duration, References, ReferencePriority, DiscardPadding
+// or Additions are written as BlockGroup instead of SimpleBlock.
+type Frame struct {
+	Keyframe bool
+	// Timestamp is in the scale of timecode.
+	Timestamp int64
+	Data      []byte
+
+	// Invisible means that the frame should be decoded but not displayed.
+	Invisible bool
+	// Discardable means that the frame can be discarded during playback.
+	// It is stored only in SimpleBlock and dropped if the frame is written as BlockGroup.
+	Discardable bool
+
+	// Duration is written as BlockDuration in the scale of timecode.
+	// It is required for subtitle tracks.
+	// Segment Info.Duration is calculated from the end of the last frame
+	// including its Duration.
+	Duration int64
+	// References are the timestamps of the frames referenced by this frame.
+	// If a non-keyframe is written as BlockGroup without References,
+	// the previous frame of the track is referenced.
+	References []int64
+	// ReferencePriority is written as ReferencePriority.
+	ReferencePriority uint64
+	// DiscardPadding is the duration of the padding to be discarded
+	// at the end of the frame in nanoseconds.
+	DiscardPadding int64
+	// Additions are the additional data of the frame keyed by BlockAddID.
+	// BlockAddID must be larger than 0.
+	Additions map[uint64][]byte
+
+	// LaceIndex is the index of the frame in the laced block.
+	// It is set by FrameReader and ignored by FrameWriter.
+	LaceIndex int
+}
+
+func newFrame(trackNumber uint64, f Frame) *frame {
+	return &frame{
+		trackNumber:       trackNumber,
+		keyframe:          f.Keyframe,
+		timestamp:         f.Timestamp,
+		b:                 f.Data,
+		invisible:         f.Invisible,
+		discardable:       f.Discardable,
+		duration:          f.Duration,
+		references:        f.References,
+		referencePriority: f.ReferencePriority,
+		discardPadding:    f.DiscardPadding,
+		additions:         f.Additions,
+		laceIndex:         f.LaceIndex,
+	}
+}
+
+func (f *frame) Frame() Frame {
+	return Frame{
+		Keyframe:          f.keyframe,
+		Timestamp:         f.timestamp,
+		Data:              f.b,
+		Invisible:         f.invisible,
+		Discardable:       f.discardable,
+		Duration:          f.duration,
+		References:        f.references,
+		ReferencePriority: f.referencePriority,
+		DiscardPadding:    f.discardPadding,
+		Additions:         f.additions,
+		LaceIndex:         f.laceIndex,
+	}
+}
+
+// blockGroupRequired returns true if the frame has metadata
+// which can't be stored in SimpleBlock.
+func (f *frame) blockGroupRequired() bool {
+	return f.duration != 0 || len(f.references) > 0 ||
+		f.referencePriority != 0 || f.discardPadding != 0 ||
+		len(f.additions) > 0
+}
+
+// size returns the total size of the frame data.
+func (f *frame) size() int {
+	if len(f.lace) > 0 {
+		var n int
+		for _, l := range f.lace {
+			n += len(l.b)
+		}
+		return n
+	}
+	return len(f.b)
+}
+
+// end returns the timestamp of the end of the frame.
+func (f *frame) end() int64 {
+	if f.lacing != nil {
+		n := len(f.lace)
+		if n == 0 {
+			n = 1
+		}
+		return f.timestamp + f.lacing.offset(n)
+	}
+	return f.timestamp + f.duration
+}
+
+func validateFrame(f Frame) error {
+	if f.Duration < 0 {
+		return ErrInvalidFrame
+	}
+	if _, ok := f.Additions[0]; ok {
+		return ErrInvalidFrame
+	}
+	return nil
+}
+
+// readFrame reads a frame from BlockReader.
+// Block metadata is read if r implements FrameReader.
+func readFrame(r BlockReader) (*frame, error) {
+	if fr, ok := r.(FrameReader); ok {
+		f, err := fr.ReadFrame()
+		if err != nil {
+			return nil, err
+		}
+		return newFrame(0, f), nil
+	}
+	f := &frame{}
+	var err error
+	if f.b, f.keyframe, f.timestamp, err = r.Read(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// writeFrame writes a frame to BlockWriter.
+// Block metadata is written if w implements FrameWriter.
+func writeFrame(w BlockWriter, f *frame) (int, error) {
+	if fw, ok := w.(FrameWriter); ok {
+		return fw.WriteFrame(f.Frame())
+	}
+	return w.Write(f.keyframe, f.timestamp, f.b)
+}
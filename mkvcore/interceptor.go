@@ -54,6 +54,11 @@ func (w *filterWriter) Write(keyframe bool, timestamp int64, b []byte) (int, err
 	return len(b), nil
 }
 
+func (w *filterWriter) WriteFrame(f Frame) (int, error) {
+	w.ch <- newFrame(w.trackNumber, f)
+	return len(f.Data), nil
+}
+
 func (r *filterReader) Read() ([]byte, bool, int64, error) {
 	frame, ok := <-r.ch
 	if !ok {
@@ -62,6 +67,14 @@ func (r *filterReader) Read() ([]byte, bool, int64, error) {
 	return frame.b, frame.keyframe, frame.timestamp, nil
 }
 
+func (r *filterReader) ReadFrame() (Frame, error) {
+	frame, ok := <-r.ch
+	if !ok {
+		return Frame{}, io.EOF
+	}
+	return frame.Frame(), nil
+}
+
 func (r *filterReader) close() {
 	close(r.ch)
 }
@@ -154,12 +167,12 @@ func (s *multiTrackBlockSorter) Intercept(r []BlockReader, w []BlockWriter) {
 	for i, r := range r {
 		go func(i int, r BlockReader) {
 			for {
-				var err error
-				f := &frame{trackNumber: uint64(i)}
-				if f.b, f.keyframe, f.timestamp, err = r.Read(); err != nil {
+				f, err := readFrame(r)
+				if err != nil {
 					wg.Done()
 					return
 				}
+				f.trackNumber = uint64(i)
 				ch <- f
 			}
 		}(i, r)
@@ -214,7 +227,7 @@ func (s *multiTrackBlockSorter) Intercept(r []BlockReader, w []BlockWriter) {
 				(nMax > s.options.maxDelayedPackets && s.options.maxDelayedPackets != 0) ||
 				(largestTimestampDelta > s.options.maxTimescaleDelay && s.options.maxTimescaleDelay != 0) {
 				fOldest := bOldest.Pop()
-				_, _ = w[fOldest.trackNumber].Write(fOldest.keyframe, fOldest.timestamp, fOldest.b)
+				_, _ = writeFrame(w[fOldest.trackNumber], fOldest)
 				tDone = fOldest.timestamp
 			} else {
 				break
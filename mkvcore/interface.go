@@ -27,6 +27,20 @@ type BlockReader interface {
 	Read() (b []byte, keyframe bool, timestamp int64, err error)
 }
 
+// FrameWriter is a Matroska frame writer interface.
+// BlockWriters created by NewSimpleBlockWriter implement it.
+type FrameWriter interface {
+	// WriteFrame writes a frame with the block metadata.
+	WriteFrame(f Frame) (int, error)
+}
+
+// FrameReader is a Matroska frame reader interface.
+// BlockReaders created by NewSimpleBlockReader implement it.
+type FrameReader interface {
+	// ReadFrame reads a frame with the block metadata.
+	ReadFrame() (Frame, error)
+}
+
 // BlockCloser is a Matroska closer interface.
 type BlockCloser interface {
 	// Close the stream frame writer.
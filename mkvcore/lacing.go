@@ -0,0 +1,149 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mkvcore
+
+import (
+	"bytes"
+
+	"github.com/at-wat/ebml-go"
+)
+
+// maxLacedFrames is the maximum number of frames in a laced block.
+const maxLacedFrames = 0xFF
+
+// trackLacing stores lacing parameters of the track.
+type trackLacing struct {
+	// defaultDuration is DefaultDuration of the track in nanoseconds.
+	defaultDuration uint64
+	timecodeScale   uint64
+	budget          ebml.LaceBudget
+}
+
+// readTrackLacing reads FlagLacing and DefaultDuration from TrackEntry
+// and TimecodeScale from Segment.Info.
+// TrackEntry and Info can be any type which can be marshalled.
+func readTrackLacing(trackEntry, info interface{}, budget ebml.LaceBudget) (*trackLacing, error) {
+	var buf bytes.Buffer
+	src := struct {
+		Info       interface{} `ebml:"Info,omitempty"`
+		TrackEntry interface{} `ebml:"TrackEntry,omitempty"`
+	}{info, trackEntry}
+	if err := ebml.Marshal(&src, &buf); err != nil {
+		return nil, err
+	}
+	dst := struct {
+		Info struct {
+			TimecodeScale uint64
+		}
+		TrackEntry struct {
+			FlagLacing      uint64
+			DefaultDuration uint64
+		}
+	}{}
+	dst.Info.TimecodeScale = ebml.DefaultTimecodeScale
+	dst.TrackEntry.FlagLacing = 1
+	if err := ebml.Unmarshal(&buf, &dst); err != nil {
+		return nil, err
+	}
+	if dst.TrackEntry.FlagLacing == 0 {
+		return nil, ErrLacingDisabled
+	}
+	if dst.TrackEntry.DefaultDuration == 0 {
+		return nil, ErrLacingRequiresDefaultDuration
+	}
+	if dst.Info.TimecodeScale == 0 {
+		dst.Info.TimecodeScale = ebml.DefaultTimecodeScale
+	}
+	return &trackLacing{
+		defaultDuration: dst.TrackEntry.DefaultDuration,
+		timecodeScale:   dst.Info.TimecodeScale,
+		budget:          budget,
+	}, nil
+}
+
+// offset returns the timestamp offset of the n-th frame in the lace
+// in the scale of timecode.
+func (l *trackLacing) offset(n int) int64 {
+	return int64((uint64(n)*l.defaultDuration + l.timecodeScale/2) / l.timecodeScale)
+}
+
+// laceBuffer packs consecutive frames of a track into laced blocks.
+type laceBuffer struct {
+	*trackLacing
+	frames []*frame
+}
+
+// add appends the frame to the pending lace.
+// It returns false if the frame can't be a part of the pending lace.
+func (b *laceBuffer) add(f *frame) bool {
+	if f.blockGroupRequired() {
+		return false
+	}
+	n := len(b.frames)
+	if n == 0 {
+		b.frames = append(b.frames, f)
+		return true
+	}
+	head := b.frames[0]
+	if n >= maxLacedFrames ||
+		f.keyframe != head.keyframe ||
+		f.invisible != head.invisible ||
+		f.discardable != head.discardable {
+		return false
+	}
+	if b.budget.MaxDuration > 0 && b.offset(n+1) > b.budget.MaxDuration {
+		return false
+	}
+	// Timestamps of the laced frames are derived from DefaultDuration.
+	// Allow an error of 1 caused by rounding.
+	if d := f.timestamp - head.timestamp - b.offset(n); d < -1 || 1 < d {
+		return false
+	}
+	b.frames = append(b.frames, f)
+	return true
+}
+
+// flush returns the pending lace as frames and clears the buffer.
+// A frame with multiple data is returned for each laced block
+// split within the byte budget.
+func (b *laceBuffer) flush() ([]*frame, error) {
+	if len(b.frames) == 0 {
+		return nil, nil
+	}
+	frames := b.frames
+	b.frames = nil
+
+	if len(frames) == 1 {
+		frames[0].lacing = b.trackLacing
+		return frames, nil
+	}
+	block := &ebml.Block{}
+	for _, f := range frames {
+		block.Data = append(block.Data, f.b)
+	}
+	blocks, err := ebml.SplitLacedBlocks(block, 0, ebml.LaceBudget{MaxBytes: b.budget.MaxBytes})
+	if err != nil {
+		return nil, err
+	}
+	var ret []*frame
+	for _, blk := range blocks {
+		head := *frames[0]
+		head.lace = frames[:len(blk.Data)]
+		head.lacing = b.trackLacing
+		frames = frames[len(blk.Data):]
+		ret = append(ret, &head)
+	}
+	return ret, nil
+}
@@ -23,15 +23,27 @@ import (
 // ErrInvalidTrackNumber means that a track number is invalid. The track number must be larger than 0.
 var ErrInvalidTrackNumber = errors.New("invalid track number")
 
-// ErrCuesRequiresSeekHead means WithCues was used without WithSeekHead.
+// ErrCuesRequiresSeekHead means WithCues or WithCuesAtEnd was used without WithSeekHead.
 var ErrCuesRequiresSeekHead = errors.New("WithCues requires WithSeekHead")
 
-// ErrCuesRequiresSeeker means WithCues was used with a writer that does not implement io.WriteSeeker.
+// ErrCuesRequiresSeeker means WithCues or WithCuesAtEnd was used with a writer that does not implement io.WriteSeeker.
 var ErrCuesRequiresSeeker = errors.New("WithCues requires an io.WriteSeeker")
 
+// ErrCuesModeConflict means both WithCues and WithCuesAtEnd were used.
+var ErrCuesModeConflict = errors.New("WithCues and WithCuesAtEnd can't be used together")
+
+// ErrKnownSizeRequiresSeeker means WithKnownSize was used with a writer that does not implement io.WriteSeeker.
+var ErrKnownSizeRequiresSeeker = errors.New("WithKnownSize requires an io.WriteSeeker")
+
 // ErrCuesReservedTooSmall means WithCues was called with a reservedSize smaller than 9 bytes.
 var ErrCuesReservedTooSmall = errors.New("WithCues reservedSize must be at least 9")
 
+// ErrLacingDisabled means WithLacing was used for a track with FlagLacing of 0.
+var ErrLacingDisabled = errors.New("lacing is disabled by FlagLacing")
+
+// ErrLacingRequiresDefaultDuration means WithLacing was used for a track without DefaultDuration.
+var ErrLacingRequiresDefaultDuration = errors.New("WithLacing requires DefaultDuration")
+
 // durationSettable is implemented by segment info types that support
 // having their Duration field set automatically (e.g. webm.Info).
 type durationSettable interface {
@@ -102,6 +114,10 @@ type BlockWriterOptions struct {
 	mainTrackNumber     uint64
 	maxKeyframeInterval int64
 	cuesReservedSize    int
+	cuesAtEnd           bool
+	knownSize           bool
+	clusterPolicies     []ClusterPolicy
+	lacing              map[uint64]ebml.LaceBudget
 }
 
 // WithEBMLHeader sets EBML header.
@@ -160,6 +176,30 @@ func WithCues(reservedSize int) BlockWriterOptionFn {
 	}
 }
 
+// WithCuesAtEnd enables writing a Cues (seek index) element after the last Cluster
+// and calculates Duration on finish.
+// SeekHead entry of the Cues is rewritten to point it, so the index is always complete
+// regardless of the length of the stream without reserving the space.
+// Requires WithSeekHead(true) and an io.WriteSeeker, not just io.WriteCloser.
+// It can't be used with WithCues.
+func WithCuesAtEnd() BlockWriterOptionFn {
+	return func(o *BlockWriterOptions) error {
+		o.cuesAtEnd = true
+		return nil
+	}
+}
+
+// WithKnownSize enables writing the sizes of Segment and Clusters.
+// 8-bytes data size fields are reserved and overwritten by the actual sizes
+// when each Cluster is closed and on finish.
+// Requires an io.WriteSeeker, not just io.WriteCloser.
+func WithKnownSize() BlockWriterOptionFn {
+	return func(o *BlockWriterOptions) error {
+		o.knownSize = true
+		return nil
+	}
+}
+
 // WithMaxKeyframeInterval sets maximum keyframe interval of the main (video) track.
 // Using this option starts the cluster with a key frame if possible.
 // interval must be given in the scale of timecode.
@@ -174,6 +214,61 @@ func WithMaxKeyframeInterval(mainTrackNumber uint64, interval int64) BlockWriter
 	}
 }
 
+// WithLacing enables lacing of the frames on the track.
+// Consecutive frames spaced by DefaultDuration of the TrackEntry are packed
+// into a laced SimpleBlock within the budget.
+// budget.MaxDuration must be given in the scale of timecode.
+// The TrackEntry must have DefaultDuration and must not have FlagLacing of 0.
+// Laced block is written when the following frame of the track doesn't fit in it,
+// so it may be placed after the blocks of the other tracks with later timestamps.
+func WithLacing(trackNumber uint64, budget ebml.LaceBudget) BlockWriterOptionFn {
+	return func(o *BlockWriterOptions) error {
+		if trackNumber == 0 {
+			return ErrInvalidTrackNumber
+		}
+		if o.lacing == nil {
+			o.lacing = make(map[uint64]ebml.LaceBudget)
+		}
+		o.lacing[trackNumber] = budget
+		return nil
+	}
+}
+
+// WithClusterPolicy registers ClusterPolicy to decide the boundaries of the Clusters.
+// A new Cluster is started if any of the registered policies returns true,
+// in addition to the case that the block timecode exceeds the range of int16.
+func WithClusterPolicy(p ClusterPolicy) BlockWriterOptionFn {
+	return func(o *BlockWriterOptions) error {
+		o.clusterPolicies = append(o.clusterPolicies, p)
+		return nil
+	}
+}
+
+// WithMaxClusterDuration limits the duration of the Clusters.
+// d must be given in the scale of timecode.
+func WithMaxClusterDuration(d int64) BlockWriterOptionFn {
+	return WithClusterPolicy(maxClusterDurationPolicy(d))
+}
+
+// WithMaxClusterSize limits the size of the Clusters.
+// size is compared with the total size of the Cluster header and the frame data,
+// so the actual Cluster may exceed it by the size of the block headers.
+// A Cluster contains at least one block even if the block exceeds size.
+func WithMaxClusterSize(size int) BlockWriterOptionFn {
+	return WithClusterPolicy(maxClusterSizePolicy(size))
+}
+
+// WithClusterOnKeyframe starts a new Cluster on every keyframe of the track.
+func WithClusterOnKeyframe(trackNumber uint64) BlockWriterOptionFn {
+	return func(o *BlockWriterOptions) error {
+		if trackNumber == 0 {
+			return ErrInvalidTrackNumber
+		}
+		o.clusterPolicies = append(o.clusterPolicies, clusterOnKeyframePolicy(trackNumber))
+		return nil
+	}
+}
+
 // BlockReaderOptionFn configures a BlockReaderOptions.
 type BlockReaderOptionFn func(*BlockReaderOptions) error
 
@@ -20,7 +20,19 @@ import (
 	"github.com/at-wat/ebml-go"
 )
 
-func setSeekHead(header *flexHeader, withCues bool, opts ...ebml.MarshalOption) (segmentDataStart, durationElementPos uint64, err error) {
+// seekHeadLayout stores the positions of the elements to be overwritten at finalization.
+type seekHeadLayout struct {
+	segmentDataStart   uint64
+	durationElementPos uint64
+	// cuesSeekPos and cuesSeekSize are the position and the total size of
+	// the Seek element pointing Cues.
+	cuesSeekPos  uint64
+	cuesSeekSize uint64
+	// cuesSeekPositionPos is the position of SeekPosition data pointing Cues.
+	cuesSeekPositionPos uint64
+}
+
+func setSeekHead(header *flexHeader, withCues bool, opts ...ebml.MarshalOption) (*seekHeadLayout, error) {
 	infoPos := new(uint64)
 	tracksPos := new(uint64)
 	header.Segment.SeekHead = &seekHeadFixed{}
@@ -43,9 +55,16 @@ func setSeekHead(header *flexHeader, withCues bool, opts ...ebml.MarshalOption)
 		})
 	}
 
+	layout := &seekHeadLayout{}
 	var segmentPos uint64
 	hook := func(e *ebml.Element) {
 		switch e.Name {
+		case "Seek":
+			// Cues is the last entry.
+			layout.cuesSeekPos = e.Position
+			layout.cuesSeekSize = e.HeaderSize + e.Size
+		case "SeekPosition":
+			layout.cuesSeekPositionPos = e.DataPosition
 		case "SeekHead":
 			// SeekHead position is the top of the Segment contents.
 			// Origin of the segment position is here.
@@ -55,6 +74,11 @@ func setSeekHead(header *flexHeader, withCues bool, opts ...ebml.MarshalOption)
 		case "Tracks":
 			*tracksPos = e.Position - segmentPos
 		}
+		// Duration is overwritten in place at finalization,
+		// so it must be encoded as 8-bytes float.
+		if e.Type == ebml.ElementDuration && e.HeaderSize == 3 && e.Size == 8 {
+			layout.durationElementPos = e.Position
+		}
 	}
 
 	optsWithHook := append([]ebml.MarshalOption{}, opts...)
@@ -62,7 +86,7 @@ func setSeekHead(header *flexHeader, withCues bool, opts ...ebml.MarshalOption)
 
 	var buf bytes.Buffer
 	if err := ebml.Marshal(header, &buf, optsWithHook...); err != nil {
-		return 0, 0, err
+		return nil, err
 	}
 
 	// The Void (reserved for Cues) starts right after the header.
@@ -71,15 +95,9 @@ func setSeekHead(header *flexHeader, withCues bool, opts ...ebml.MarshalOption)
 		*cuesPos = uint64(buf.Len()) - segmentPos
 	}
 
-	// Find Duration element position by scanning the temporary buffer.
-	// We can't use the hook because child element positions don't account
-	// for the parent's VINT size (which is written after content for
-	// known-size elements). Byte scanning the buffer is exact.
-	// Duration element: ID 0x44 0x89, VINT 0x88 (8-byte float64).
-	durationPattern := []byte{0x44, 0x89, 0x88}
-	if idx := bytes.Index(buf.Bytes(), durationPattern); idx >= 0 {
-		durationElementPos = uint64(idx)
+	if !withCues {
+		layout.cuesSeekPos, layout.cuesSeekSize, layout.cuesSeekPositionPos = 0, 0, 0
 	}
-
-	return segmentPos, durationElementPos, nil
+	layout.segmentDataStart = segmentPos
+	return layout, nil
 }
@@ -0,0 +1,95 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mkvcore
+
+import (
+	"io"
+
+	"github.com/at-wat/ebml-go"
+)
+
+// sizePatcher overwrites unknown sizes of Segment and Cluster
+// by the actual sizes on io.WriteSeeker.
+// Unknown data sizes are written as 8-bytes VINT, so the known sizes can be
+// written in place.
+type sizePatcher struct {
+	seeker io.WriteSeeker
+	// segmentDataPos and clusterDataPos are the positions of the element data.
+	// Zero means that the element is not open.
+	segmentDataPos int64
+	clusterDataPos int64
+}
+
+// marshal marshals v at the end of the stream and stores
+// the data positions of Segment and Cluster.
+func (p *sizePatcher) marshal(v interface{}, w io.Writer, opts []ebml.MarshalOption) error {
+	pos, err := p.seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	hook := func(e *ebml.Element) {
+		switch e.Type {
+		case ebml.ElementSegment:
+			p.segmentDataPos = pos + int64(e.DataPosition)
+		case ebml.ElementCluster:
+			p.clusterDataPos = pos + int64(e.DataPosition)
+		}
+	}
+	optsWithHook := append([]ebml.MarshalOption{}, opts...)
+	optsWithHook = append(optsWithHook, ebml.WithElementWriteHooks(hook))
+	return ebml.Marshal(v, w, optsWithHook...)
+}
+
+// closeCluster writes the size of the open Cluster.
+func (p *sizePatcher) closeCluster() error {
+	if p.clusterDataPos == 0 {
+		return nil
+	}
+	err := p.patch(p.clusterDataPos)
+	p.clusterDataPos = 0
+	return err
+}
+
+// closeSegment writes the size of the Segment.
+func (p *sizePatcher) closeSegment() error {
+	if p.segmentDataPos == 0 {
+		return nil
+	}
+	err := p.patch(p.segmentDataPos)
+	p.segmentDataPos = 0
+	return err
+}
+
+// patch writes the size of the element from dataPos to the end of the stream.
+func (p *sizePatcher) patch(dataPos int64) error {
+	end, err := p.seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	size := uint64(end - dataPos)
+	b := []byte{
+		0x01,
+		byte(size >> 48), byte(size >> 40), byte(size >> 32),
+		byte(size >> 24), byte(size >> 16), byte(size >> 8), byte(size),
+	}
+	if _, err := p.seeker.Seek(dataPos-int64(len(b)), io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := p.seeker.Write(b); err != nil {
+		return err
+	}
+	_, err = p.seeker.Seek(0, io.SeekEnd)
+	return err
+}
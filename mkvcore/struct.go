@@ -18,9 +18,22 @@ import (
 	"github.com/at-wat/ebml-go"
 )
 
+type blockMore struct {
+	BlockAddID      uint64 `ebml:"BlockAddID"`
+	BlockAdditional []byte `ebml:"BlockAdditional"`
+}
+
+type blockAdditions struct {
+	BlockMore []blockMore `ebml:"BlockMore"`
+}
+
 type simpleBlockGroup struct {
-	Block             []ebml.Block `ebml:"Block"`
-	ReferencePriority uint64       `ebml:"ReferencePriority"`
+	Block             []ebml.Block    `ebml:"Block"`
+	BlockAdditions    *blockAdditions `ebml:"BlockAdditions,omitempty"`
+	BlockDuration     uint64          `ebml:"BlockDuration,omitempty"`
+	ReferencePriority uint64          `ebml:"ReferencePriority,omitempty"`
+	ReferenceBlock    []int64         `ebml:"ReferenceBlock"`
+	DiscardPadding    int64           `ebml:"DiscardPadding,omitempty"`
 }
 
 type simpleBlockCluster struct {
@@ -71,20 +84,30 @@ type flexHeader struct {
 
 // TrackEntry is a TrackEntry struct with all mandatory elements and commonly used elements.
 type TrackEntry struct {
-	TrackNumber        uint64
-	TrackUID           uint64
-	TrackType          uint8
-	FlagEnabled        uint8
-	FlagDefault        uint8
-	FlagForced         uint8
-	FlagLacing         uint8
-	MinCache           uint64
-	DefaultDuration    uint64
-	MaxBlockAdditionID uint64
-	Name               string
-	Language           string
-	LanguageIETF       string
-	CodecID            string
-	CodecDecodeAll     uint8
-	SeekPreRoll        uint64
+	TrackNumber          uint64
+	TrackUID             uint64
+	TrackType            uint8
+	FlagEnabled          uint8
+	FlagDefault          uint8
+	FlagForced           uint8
+	FlagLacing           uint8
+	MinCache             uint64
+	DefaultDuration      uint64
+	MaxBlockAdditionID   uint64
+	BlockAdditionMapping []BlockAdditionMapping
+	Name                 string
+	Language             string
+	LanguageIETF         string
+	CodecID              string
+	CodecDecodeAll       uint8
+	SeekPreRoll          uint64
+}
+
+// BlockAdditionMapping is a BlockAdditionMapping struct to declare the contents of
+// BlockAdditional data stored in the track.
+type BlockAdditionMapping struct {
+	BlockAddIDValue     uint64
+	BlockAddIDName      string
+	BlockAddIDType      uint64
+	BlockAddIDExtraData []byte
 }
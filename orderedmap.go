@@ -0,0 +1,125 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"reflect"
+	"sort"
+)
+
+// OrderedMapItem is a pair of the element name and the value stored in OrderedMap.
+type OrderedMapItem struct {
+	Key   string
+	Value interface{}
+}
+
+// OrderedMap is an element container which keeps the order of the elements.
+//
+// Unlike map[string]interface{}, items are marshalled in the stored order and
+// multiple items can have the same key.
+// Unmarshalling to OrderedMap stores the elements in the order of appearance,
+// and master elements are stored as *OrderedMap.
+type OrderedMap struct {
+	Items []OrderedMapItem
+}
+
+var orderedMapType = reflect.TypeOf(OrderedMap{})
+
+// Get returns the value of the first item with the key.
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	for _, item := range m.Items {
+		if item.Key == key {
+			return item.Value, true
+		}
+	}
+	return nil, false
+}
+
+// GetAll returns the values of all items with the key.
+func (m *OrderedMap) GetAll(key string) []interface{} {
+	var vals []interface{}
+	for _, item := range m.Items {
+		if item.Key == key {
+			vals = append(vals, item.Value)
+		}
+	}
+	return vals
+}
+
+// Set replaces the value of the first item with the key.
+// The item is appended if the key is not present.
+func (m *OrderedMap) Set(key string, value interface{}) {
+	for i := range m.Items {
+		if m.Items[i].Key == key {
+			m.Items[i].Value = value
+			return
+		}
+	}
+	m.Add(key, value)
+}
+
+// Add appends an item.
+func (m *OrderedMap) Add(key string, value interface{}) {
+	m.Items = append(m.Items, OrderedMapItem{Key: key, Value: value})
+}
+
+// Len returns the number of the items.
+func (m *OrderedMap) Len() int {
+	return len(m.Items)
+}
+
+// segmentChildOrder is the recommended order of the top level elements in Segment.
+var segmentChildOrder = map[ElementType]int{
+	ElementSeekHead:    1,
+	ElementInfo:        2,
+	ElementTracks:      3,
+	ElementChapters:    4,
+	ElementAttachments: 5,
+	ElementTags:        6,
+	ElementCluster:     7,
+	ElementCues:        8,
+}
+
+// compareSchemaOrder compares the elements by the schema order.
+// It returns negative value if ti comes before tj.
+func compareSchemaOrder(ti, tj ElementType) int {
+	oi, okI := segmentChildOrder[ti]
+	oj, okJ := segmentChildOrder[tj]
+	if okI && okJ {
+		return oi - oj
+	}
+	return int(ti) - int(tj)
+}
+
+// sortMapKeys sorts map keys by the schema order of the element.
+// Keys are sorted by name if the element order is same.
+func sortMapKeys(keys []reflect.Value) {
+	elementType := func(k reflect.Value) ElementType {
+		if k.Kind() != reflect.String {
+			return ElementInvalid
+		}
+		t, _ := ElementTypeFromString(k.String())
+		return t
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if c := compareSchemaOrder(elementType(keys[i]), elementType(keys[j])); c != 0 {
+			return c < 0
+		}
+		if keys[i].Kind() != reflect.String || keys[j].Kind() != reflect.String {
+			return false
+		}
+		return keys[i].String() < keys[j].String()
+	})
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := &OrderedMap{}
+	m.Add("Cluster", 1)
+	m.Add("Cues", 2)
+	m.Add("Cluster", 3)
+	m.Set("Cues", 4)
+	m.Set("Tags", 5)
+
+	expected := []OrderedMapItem{
+		{Key: "Cluster", Value: 1},
+		{Key: "Cues", Value: 4},
+		{Key: "Cluster", Value: 3},
+		{Key: "Tags", Value: 5},
+	}
+	if !reflect.DeepEqual(expected, m.Items) {
+		t.Errorf("Unexpected items,\nexpected: %v\n     got: %v", expected, m.Items)
+	}
+	if n := m.Len(); n != 4 {
+		t.Errorf("Expected length: 4, got: %d", n)
+	}
+	if v, ok := m.Get("Cluster"); !ok || v != 1 {
+		t.Errorf("Expected Get result: (1, true), got: (%v, %v)", v, ok)
+	}
+	if v, ok := m.Get("Info"); ok {
+		t.Errorf("Expected Get result: (nil, false), got: (%v, %v)", v, ok)
+	}
+	if v := m.GetAll("Cluster"); !reflect.DeepEqual([]interface{}{1, 3}, v) {
+		t.Errorf("Expected GetAll result: [1 3], got: %v", v)
+	}
+}
+
+func TestOrderedMap_Roundtrip(t *testing.T) {
+	b := []byte{
+		0x1A, 0x45, 0xDF, 0xA3, 0x88,
+		0x42, 0x82, 0x85, 0x68, 0x6F, 0x67, 0x65, 0x00,
+		0x18, 0x53, 0x80, 0x67, 0x95,
+		0x1F, 0x43, 0xB6, 0x75, 0x83,
+		0xE7, 0x81, 0x01,
+		0x1C, 0x53, 0xBB, 0x6B, 0x80,
+		0x1F, 0x43, 0xB6, 0x75, 0x83,
+		0xE7, 0x81, 0x02,
+	}
+	expected := OrderedMap{
+		Items: []OrderedMapItem{
+			{Key: "EBML", Value: &OrderedMap{
+				Items: []OrderedMapItem{{Key: "EBMLDocType", Value: "hoge"}},
+			}},
+			{Key: "Segment", Value: &OrderedMap{
+				Items: []OrderedMapItem{
+					{Key: "Cluster", Value: &OrderedMap{
+						Items: []OrderedMapItem{{Key: "Timestamp", Value: uint64(1)}},
+					}},
+					{Key: "Cues", Value: &OrderedMap{}},
+					{Key: "Cluster", Value: &OrderedMap{
+						Items: []OrderedMapItem{{Key: "Timestamp", Value: uint64(2)}},
+					}},
+				},
+			}},
+		},
+	}
+
+	runForEachReader(t, b, func(t *testing.T, r func() io.Reader) {
+		var ret OrderedMap
+		if err := Unmarshal(r(), &ret); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		if !reflect.DeepEqual(expected, ret) {
+			t.Fatalf("Unmarshal to OrderedMap differs from expected:\n%#+v\ngot:\n%#+v", expected, ret)
+		}
+
+		var buf bytes.Buffer
+		if err := Marshal(&ret, &buf); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		// Trailing null character of the string is removed.
+		expectedBytes := []byte{
+			0x1A, 0x45, 0xDF, 0xA3, 0x87,
+			0x42, 0x82, 0x84, 0x68, 0x6F, 0x67, 0x65,
+		}
+		expectedBytes = append(expectedBytes, b[13:]...)
+		if !bytes.Equal(expectedBytes, buf.Bytes()) {
+			t.Errorf("Marshaled binary doesn't match:\n expected: %v,\n      got: %v", expectedBytes, buf.Bytes())
+		}
+	})
+}
@@ -15,14 +15,367 @@
 package ebml
 
 import (
-	"bytes"
+	"bufio"
 	"io"
+	"io/ioutil"
+	"sync"
 )
 
+const bufferedReaderSize = 4096
+
+// sliceReader is implemented by the readers which can return next n bytes
+// without copying if the source is a byte slice.
+type sliceReader interface {
+	readSlice(n uint64) ([]byte, error)
+}
+
+// readByte reads one byte using io.ByteReader if implemented.
+func readByte(r io.Reader) (byte, error) {
+	if br, ok := r.(io.ByteReader); ok {
+		return br.ReadByte()
+	}
+	var b [1]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// readSlice reads next n bytes.
+// Returned slice may share the memory with the source if r implements sliceReader.
+func readSlice(r io.Reader, n uint64) ([]byte, error) {
+	if sr, ok := r.(sliceReader); ok {
+		return sr.readSlice(n)
+	}
+	b := make([]byte, n)
+	_, err := io.ReadFull(r, b)
+	return b, err
+}
+
+// skipper is implemented by the readers which can discard next n bytes
+// without reading them.
+type skipper interface {
+	skip(n uint64) error
+}
+
+// skip discards next n bytes.
+// The bytes are skipped by seeking if r implements io.Seeker.
+func skip(r io.Reader, n uint64) error {
+	switch s := r.(type) {
+	case skipper:
+		return s.skip(n)
+	case io.Seeker:
+		if ok, err := seekForward(s, n); ok {
+			return err
+		}
+	}
+	return discard(r, n)
+}
+
+// discard reads and discards next n bytes.
+func discard(r io.Reader, n uint64) error {
+	switch _, err := io.CopyN(ioutil.Discard, r, int64(n)); err {
+	case nil:
+		return nil
+	case io.EOF:
+		return io.ErrUnexpectedEOF
+	default:
+		return err
+	}
+}
+
+// seekForward seeks s forward by n bytes.
+// It returns false if s is not seekable, e.g. s is a pipe.
+// io.ErrUnexpectedEOF is returned if the stream ends before n bytes
+// and the position is left at the end.
+func seekForward(s io.Seeker, n uint64) (bool, error) {
+	cur, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return false, nil
+	}
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return true, err
+	}
+	next := cur + int64(n)
+	if next > end || next < cur {
+		return true, io.ErrUnexpectedEOF
+	}
+	_, err = s.Seek(next, io.SeekStart)
+	return true, err
+}
+
+// seekable returns true if r can be seeked from the current position.
+func seekable(r io.Reader) bool {
+	s, ok := r.(io.Seeker)
+	if !ok {
+		return false
+	}
+	_, err := s.Seek(0, io.SeekCurrent)
+	return err == nil
+}
+
+// readerAtOf returns io.ReaderAt and the current offset of r if available.
+func readerAtOf(r io.Reader) (io.ReaderAt, int64) {
+	ra, ok := r.(io.ReaderAt)
+	if !ok {
+		return nil, 0
+	}
+	s, ok := r.(io.Seeker)
+	if !ok {
+		return nil, 0
+	}
+	off, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, 0
+	}
+	return ra, off
+}
+
+// byteSliceReader reads a byte slice without copying.
+type byteSliceReader struct {
+	b []byte
+}
+
+func (r *byteSliceReader) Read(b []byte) (int, error) {
+	if len(r.b) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(b, r.b)
+	r.b = r.b[n:]
+	return n, nil
+}
+
+func (r *byteSliceReader) ReadByte() (byte, error) {
+	if len(r.b) == 0 {
+		return 0, io.EOF
+	}
+	b := r.b[0]
+	r.b = r.b[1:]
+	return b, nil
+}
+
+func (r *byteSliceReader) skip(n uint64) error {
+	if n > uint64(len(r.b)) {
+		r.b = nil
+		return io.ErrUnexpectedEOF
+	}
+	r.b = r.b[n:]
+	return nil
+}
+
+func (r *byteSliceReader) readSlice(n uint64) ([]byte, error) {
+	l := uint64(len(r.b))
+	switch {
+	case n == 0:
+		return []byte{}, nil
+	case l == 0:
+		return nil, io.EOF
+	case n > l:
+		r.b = nil
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := r.b[:n:n]
+	r.b = r.b[n:]
+	return b, nil
+}
+
+// limitedReader is io.LimitedReader supporting io.ByteReader and sliceReader.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *limitedReader) Read(b []byte) (int, error) {
+	if r.n <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(b)) > r.n {
+		b = b[:r.n]
+	}
+	n, err := r.r.Read(b)
+	r.n -= int64(n)
+	return n, err
+}
+
+func (r *limitedReader) ReadByte() (byte, error) {
+	if r.n <= 0 {
+		return 0, io.EOF
+	}
+	b, err := readByte(r.r)
+	if err == nil {
+		r.n--
+	}
+	return b, err
+}
+
+func (r *limitedReader) readSlice(n uint64) ([]byte, error) {
+	if r.n <= 0 && n > 0 {
+		return nil, io.EOF
+	}
+	if n > uint64(r.n) {
+		_, _ = readSlice(r.r, uint64(r.n))
+		r.n = 0
+		return nil, io.ErrUnexpectedEOF
+	}
+	b, err := readSlice(r.r, n)
+	r.n -= int64(len(b))
+	return b, err
+}
+
+func (r *limitedReader) skip(n uint64) error {
+	if n > uint64(r.n) {
+		err := skip(r.r, uint64(r.n))
+		r.n = 0
+		if err != nil {
+			return err
+		}
+		return io.ErrUnexpectedEOF
+	}
+	err := skip(r.r, n)
+	if err == nil {
+		r.n -= int64(n)
+	}
+	return err
+}
+
+// prefixedReader reads head and then r.
+type prefixedReader struct {
+	head []byte
+	r    io.Reader
+}
+
+func (r *prefixedReader) Read(b []byte) (int, error) {
+	if len(r.head) == 0 {
+		return r.r.Read(b)
+	}
+	n := copy(b, r.head)
+	r.head = r.head[n:]
+	return n, nil
+}
+
+func (r *prefixedReader) ReadByte() (byte, error) {
+	if len(r.head) == 0 {
+		return readByte(r.r)
+	}
+	b := r.head[0]
+	r.head = r.head[1:]
+	return b, nil
+}
+
+func (r *prefixedReader) readSlice(n uint64) ([]byte, error) {
+	if len(r.head) == 0 {
+		return readSlice(r.r, n)
+	}
+	b := make([]byte, n)
+	_, err := io.ReadFull(r, b)
+	return b, err
+}
+
+func (r *prefixedReader) skip(n uint64) error {
+	if l := uint64(len(r.head)); n > l {
+		r.head = nil
+		return skip(r.r, n-l)
+	}
+	r.head = r.head[n:]
+	return nil
+}
+
+// captureReader stores the bytes read from r.
+type captureReader struct {
+	r   io.Reader
+	buf []byte
+}
+
+func (r *captureReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.buf = append(r.buf, b[:n]...)
+	return n, err
+}
+
+func (r *captureReader) ReadByte() (byte, error) {
+	b, err := readByte(r.r)
+	if err == nil {
+		r.buf = append(r.buf, b)
+	}
+	return b, err
+}
+
+// dataFirstReader suppresses io.EOF returned with the data.
+type dataFirstReader struct {
+	io.Reader
+}
+
+func (r *dataFirstReader) Read(b []byte) (int, error) {
+	n, err := r.Reader.Read(b)
+	if n != 0 && err == io.EOF {
+		err = nil
+	}
+	return n, err
+}
+
+// bufferedReader is a pooled buffered reader to reduce the number of
+// read calls on the source.
+type bufferedReader struct {
+	*bufio.Reader
+	src dataFirstReader
+}
+
+var bufferedReaderPool = sync.Pool{
+	New: func() interface{} {
+		r := &bufferedReader{}
+		r.Reader = bufio.NewReaderSize(&r.src, bufferedReaderSize)
+		return r
+	},
+}
+
+func newBufferedReader(r io.Reader) *bufferedReader {
+	br := bufferedReaderPool.Get().(*bufferedReader)
+	br.src.Reader = r
+	br.Reader.Reset(&br.src)
+	return br
+}
+
+// skip discards next n bytes.
+// If the buffered data is shorter than n and the source implements io.Seeker,
+// the rest is skipped by seeking the source.
+func (r *bufferedReader) skip(n uint64) error {
+	if b := uint64(r.Buffered()); n > b {
+		if s, ok := r.src.Reader.(io.Seeker); ok {
+			if _, err := r.Discard(int(b)); err != nil {
+				return err
+			}
+			ok, err := seekForward(s, n-b)
+			if ok {
+				r.Reader.Reset(&r.src)
+				return err
+			}
+			n -= b
+		}
+	}
+	return discard(r.Reader, n)
+}
+
+// release seeks back the source by the number of unread buffered bytes
+// if the source implements io.Seeker, and puts the reader back to the pool.
+func (r *bufferedReader) release() error {
+	var err error
+	if n := r.Buffered(); n > 0 {
+		if s, ok := r.src.Reader.(io.Seeker); ok {
+			_, err = s.Seek(-int64(n), io.SeekCurrent)
+		}
+	}
+	r.src.Reader = nil
+	r.Reader.Reset(&r.src)
+	bufferedReaderPool.Put(r)
+	return err
+}
+
 type rollbackReader interface {
 	Set(io.Reader)
 	Get() io.Reader
 	Read([]byte) (int, error)
+	ReadByte() (byte, error)
 	Reset()
 	RollbackTo(int)
 }
@@ -49,16 +402,30 @@ func (r *rollbackReaderImpl) Read(b []byte) (int, error) {
 	return n, err
 }
 
+func (r *rollbackReaderImpl) ReadByte() (byte, error) {
+	b, err := readByte(r.Reader)
+	if err == nil {
+		r.buf = append(r.buf, b)
+	}
+	return b, err
+}
+
+func (r *rollbackReaderImpl) readSlice(n uint64) ([]byte, error) {
+	b, err := readSlice(r.Reader, n)
+	r.buf = append(r.buf, b...)
+	return b, err
+}
+
 func (r *rollbackReaderImpl) Reset() {
 	r.buf = r.buf[0:0]
 }
 
 func (r *rollbackReaderImpl) RollbackTo(i int) {
 	buf := r.buf
-	r.Reader = io.MultiReader(
-		bytes.NewReader(buf[i:]),
-		r.Reader,
-	)
+	r.Reader = &prefixedReader{
+		head: buf[i:],
+		r:    r.Reader,
+	}
 	r.buf = nil
 }
 
@@ -82,6 +449,18 @@ func (r *rollbackReaderNop) Read(b []byte) (int, error) {
 	return n, err
 }
 
+func (r *rollbackReaderNop) ReadByte() (byte, error) {
+	return readByte(r.Reader)
+}
+
+func (r *rollbackReaderNop) readSlice(n uint64) ([]byte, error) {
+	return readSlice(r.Reader, n)
+}
+
+func (r *rollbackReaderNop) skip(n uint64) error {
+	return skip(r.Reader, n)
+}
+
 func (*rollbackReaderNop) Reset() {
 }
 
@@ -101,3 +101,48 @@ func TestRollbackReaderNop(t *testing.T) {
 	}()
 	r.RollbackTo(1)
 }
+
+func TestSkip(t *testing.T) {
+	testCases := map[string]func([]byte) io.Reader{
+		"ByteSlice": func(b []byte) io.Reader {
+			return &byteSliceReader{b: b}
+		},
+		"Seeker": func(b []byte) io.Reader {
+			return bytes.NewReader(b)
+		},
+		"Buffered": func(b []byte) io.Reader {
+			return newBufferedReader(bytes.NewReader(b))
+		},
+		"BufferedNotSeeker": func(b []byte) io.Reader {
+			return newBufferedReader(bytes.NewBuffer(b))
+		},
+		"Limited": func(b []byte) io.Reader {
+			return &limitedReader{r: bytes.NewReader(b), n: 8}
+		},
+		"Prefixed": func(b []byte) io.Reader {
+			return &prefixedReader{head: b[:2], r: bytes.NewReader(b[2:])}
+		},
+		"Reader": func(b []byte) io.Reader {
+			return bytes.NewBuffer(b)
+		},
+	}
+	for name, newReader := range testCases {
+		newReader := newReader
+		t.Run(name, func(t *testing.T) {
+			r := newReader([]byte{0, 1, 2, 3, 4, 5, 6, 7})
+			if err := skip(r, 3); err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			b, err := readByte(r)
+			if err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			if b != 3 {
+				t.Errorf("Expected to read 3 after skip, got %d", b)
+			}
+			if err := skip(r, 5); err != io.ErrUnexpectedEOF {
+				t.Errorf("Expected error: '%v', got: '%v'", io.ErrUnexpectedEOF, err)
+			}
+		})
+	}
+}
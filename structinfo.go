@@ -0,0 +1,135 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"reflect"
+	"sync"
+)
+
+// fieldInfo stores parsed tag and element type of a struct field.
+type fieldInfo struct {
+	index int
+	tag   *structTag
+	t     ElementType
+}
+
+// structInfo stores the field plan of a struct type.
+type structInfo struct {
+	fields []fieldInfo
+	byType map[ElementType]*fieldInfo
+	// meta is the field indexes of the metadata fields.
+	meta map[metaType]int
+	err  error
+}
+
+// structInfoCache caches *structInfo for each reflect.Type.
+var structInfoCache sync.Map
+
+// getStructInfo returns the cached field plan of the struct type.
+// Tag errors are cached and returned on every call.
+func getStructInfo(t reflect.Type) (*structInfo, error) {
+	if si, ok := structInfoCache.Load(t); ok {
+		return si.(*structInfo), si.(*structInfo).err
+	}
+	si := newStructInfo(t)
+	actual, _ := structInfoCache.LoadOrStore(t, si)
+	return actual.(*structInfo), actual.(*structInfo).err
+}
+
+func newStructInfo(t reflect.Type) *structInfo {
+	n := t.NumField()
+	si := &structInfo{
+		fields: make([]fieldInfo, 0, n),
+		byType: make(map[ElementType]*fieldInfo),
+	}
+	for i := 0; i < n; i++ {
+		f := t.Field(i)
+		tag := &structTag{}
+		if n, ok := f.Tag.Lookup("ebml"); ok {
+			var err error
+			if tag, err = parseTag(n); err != nil {
+				si.err = err
+				return si
+			}
+		}
+		if tag.meta != metaNone {
+			if !isMetaDest(f.Type, tag.meta) {
+				si.err = wrapErrorf(ErrIncompatibleType, "storing metadata to %s", f.Type)
+				return si
+			}
+			if si.meta == nil {
+				si.meta = make(map[metaType]int)
+			}
+			si.meta[tag.meta] = i
+			continue
+		}
+		if tag.name == "" {
+			tag.name = f.Name
+		}
+		et, err := ElementTypeFromString(tag.name)
+		if err != nil {
+			si.err = err
+			return si
+		}
+		si.fields = append(si.fields, fieldInfo{
+			index: i,
+			tag:   tag,
+			t:     et,
+		})
+		si.byType[et] = &si.fields[len(si.fields)-1]
+	}
+	return si
+}
+
+// isMetaDest returns true if the metadata can be stored to the type.
+func isMetaDest(t reflect.Type, m metaType) bool {
+	switch m {
+	case metaRaw:
+		return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+	default:
+		switch t.Kind() {
+		case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+			return true
+		}
+		return false
+	}
+}
+
+// setMeta stores the metadata of the element read to the metadata fields of v.
+func (si *structInfo) setMeta(v reflect.Value, pos, headerSize, size uint64, raw []byte) {
+	for m, i := range si.meta {
+		f := v.Field(i)
+		switch m {
+		case metaOffset:
+			setMetaInt(f, pos)
+		case metaDataOffset:
+			setMetaInt(f, pos+headerSize)
+		case metaSize:
+			setMetaInt(f, size)
+		case metaRaw:
+			f.SetBytes(raw)
+		}
+	}
+}
+
+func setMetaInt(v reflect.Value, i uint64) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64:
+		v.SetInt(int64(i))
+	default:
+		v.SetUint(i)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/at-wat/ebml-go/internal/errs"
+)
+
+func TestGetStructInfo(t *testing.T) {
+	type TestStruct struct {
+		DocType string `ebml:"EBMLDocType,omitempty"`
+		Cluster []struct {
+			Timecode uint64
+		} `ebml:",stop"`
+	}
+	typ := reflect.TypeOf(TestStruct{})
+
+	var wg sync.WaitGroup
+	infos := make([]*structInfo, 8)
+	for i := range infos {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			si, err := getStructInfo(typ)
+			if err != nil {
+				t.Errorf("Unexpected error: '%v'", err)
+			}
+			infos[i] = si
+		}(i)
+	}
+	wg.Wait()
+
+	for _, si := range infos[1:] {
+		if si != infos[0] {
+			t.Fatal("Cached structInfo must be shared")
+		}
+	}
+	si := infos[0]
+	expected := []fieldInfo{
+		{index: 0, tag: &structTag{name: "EBMLDocType", omitEmpty: true}, t: ElementEBMLDocType},
+		{index: 1, tag: &structTag{name: "Cluster", stop: true}, t: ElementCluster},
+	}
+	if !reflect.DeepEqual(expected, si.fields) {
+		t.Errorf("Unexpected fields,\nexpected: %+v\n     got: %+v", expected, si.fields)
+	}
+	if f := si.byType[ElementCluster]; f != &si.fields[1] {
+		t.Errorf("Unexpected field of Cluster: %+v", f)
+	}
+}
+
+func TestGetStructInfo_Error(t *testing.T) {
+	testCases := map[string]struct {
+		typ reflect.Type
+		err error
+	}{
+		"InvalidTag": {
+			reflect.TypeOf(struct {
+				DocType string `ebml:"EBMLDocType,invalidtag"`
+			}{}),
+			ErrInvalidTag,
+		},
+		"IncompatibleMeta": {
+			reflect.TypeOf(struct {
+				Offset string `ebml:",offset"`
+			}{}),
+			ErrIncompatibleType,
+		},
+		"IncompatibleRaw": {
+			reflect.TypeOf(struct {
+				Raw []int `ebml:",raw"`
+			}{}),
+			ErrIncompatibleType,
+		},
+		"UnknownElementName": {
+			reflect.TypeOf(struct {
+				Unknown string
+			}{}),
+			ErrUnknownElementName,
+		},
+	}
+	for name, c := range testCases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				if _, err := getStructInfo(c.typ); !errs.Is(err, c.err) {
+					t.Errorf("Expected error: '%v', got: '%v'", c.err, err)
+				}
+			}
+		})
+	}
+}
@@ -26,6 +26,31 @@ type structTag struct {
 	size      uint64
 	omitEmpty bool
 	stop      bool
+	// scale is the nanoseconds per unit of time.Duration field.
+	scale uint64
+	// segmentScale uses TimecodeScale of the Segment as the scale.
+	segmentScale bool
+	// meta is the kind of the metadata of the enclosing element
+	// stored to the field instead of the element.
+	meta metaType
+}
+
+// metaType is the kind of the metadata field.
+type metaType int
+
+const (
+	metaNone metaType = iota
+	metaOffset
+	metaDataOffset
+	metaSize
+	metaRaw
+)
+
+var metaTypeTags = map[string]metaType{
+	"offset":     metaOffset,
+	"dataoffset": metaDataOffset,
+	"size":       metaSize,
+	"raw":        metaRaw,
 }
 
 // ErrEmptyTag means that a tag string has empty item.
@@ -57,7 +82,11 @@ func parseTag(rawtag string) (*structTag, error) {
 			case "stop":
 				tag.stop = true
 			default:
-				return nil, wrapErrorf(ErrInvalidTag, "parsing \"%s\"", t)
+				m, ok := metaTypeTags[kv[0]]
+				if !ok || tag.name != "" || tag.meta != metaNone {
+					return nil, wrapErrorf(ErrInvalidTag, "parsing \"%s\"", t)
+				}
+				tag.meta = m
 			}
 			continue
 		}
@@ -75,6 +104,19 @@ func parseTag(rawtag string) (*structTag, error) {
 				}
 				tag.size = uint64(s)
 			}
+		case "scale":
+			if kv[1] == "segment" {
+				tag.segmentScale = true
+			} else {
+				s, err := strconv.ParseUint(kv[1], 10, 64)
+				if err != nil {
+					return nil, wrapErrorf(err, "parsing \"%s\"", t)
+				}
+				if s == 0 {
+					return nil, wrapErrorf(ErrInvalidTag, "parsing \"%s\"", t)
+				}
+				tag.scale = s
+			}
 		default:
 			return nil, wrapErrorf(ErrInvalidTag, "parsing \"%s\"", t)
 		}
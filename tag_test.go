@@ -56,10 +56,50 @@ func TestParseTag(t *testing.T) {
 			"Name123,inf",
 			&structTag{name: "Name123", size: SizeUnknown}, nil,
 		},
+		"Scale": {
+			"Name123,scale=1000",
+			&structTag{name: "Name123", scale: 1000}, nil,
+		},
+		"SegmentScale": {
+			"Name123,scale=segment",
+			&structTag{name: "Name123", segmentScale: true}, nil,
+		},
+		"InvalidScale": {
+			"Name123,scale=a",
+			nil, strconv.ErrSyntax,
+		},
+		"ZeroScale": {
+			"Name123,scale=0",
+			nil, ErrInvalidTag,
+		},
 		"InvalidSize": {
 			"Name123,size=a",
 			nil, strconv.ErrSyntax,
 		},
+		"Offset": {
+			",offset",
+			&structTag{meta: metaOffset}, nil,
+		},
+		"DataOffset": {
+			",dataoffset",
+			&structTag{meta: metaDataOffset}, nil,
+		},
+		"MetaSize": {
+			",size",
+			&structTag{meta: metaSize}, nil,
+		},
+		"Raw": {
+			",raw",
+			&structTag{meta: metaRaw}, nil,
+		},
+		"MetaWithName": {
+			"Name,raw",
+			nil, ErrInvalidTag,
+		},
+		"TwoMeta": {
+			",offset,size",
+			nil, ErrInvalidTag,
+		},
 		"InvalidTag": {
 			"Name,invalidtag",
 			nil, ErrInvalidTag,
@@ -28,7 +28,10 @@ func (s *delayedBrokenReader) Read(b []byte) (int, error) {
 	p := s.n
 	s.n += len(b)
 	if s.n > s.limit {
-		return len(b) - (s.n - s.limit), io.ErrClosedPipe
+		if p >= s.limit {
+			return 0, io.ErrClosedPipe
+		}
+		return copy(b, s.b[p:s.limit]), io.ErrClosedPipe
 	}
 	copy(b, s.b[p:p+len(b)])
 	return len(b), nil
@@ -0,0 +1,64 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidUID means that the UID string is malformed.
+var ErrInvalidUID = errors.New("invalid UID")
+
+// UID128 represents 128-bit unique identifier like SegmentUID, PrevUID and NextUID.
+type UID128 [16]byte
+
+// NewUID128 generates random UID128.
+func NewUID128() (UID128, error) {
+	var u UID128
+	for u.IsZero() {
+		if _, err := rand.Read(u[:]); err != nil {
+			return UID128{}, err
+		}
+	}
+	return u, nil
+}
+
+// ParseUID128 parses hexadecimal string representation of UID128.
+// Hyphens in the string are ignored.
+func ParseUID128(s string) (UID128, error) {
+	var u UID128
+	b, err := hex.DecodeString(strings.Replace(s, "-", "", -1))
+	if err != nil {
+		return UID128{}, wrapErrorf(ErrInvalidUID, "parsing %q: %v", s, err)
+	}
+	if len(b) != len(u) {
+		return UID128{}, wrapErrorf(ErrInvalidUID, "parsing %q: %d bytes", s, len(b))
+	}
+	copy(u[:], b)
+	return u, nil
+}
+
+// String returns hexadecimal string representation of the UID.
+func (u UID128) String() string {
+	return hex.EncodeToString(u[:])
+}
+
+// IsZero returns true if the UID is not set.
+func (u UID128) IsZero() bool {
+	return u == UID128{}
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The ebml-go authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebml
+
+import (
+	"testing"
+
+	"github.com/at-wat/ebml-go/internal/errs"
+)
+
+func TestNewUID128(t *testing.T) {
+	u0, err := NewUID128()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	u1, err := NewUID128()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if u0.IsZero() || u1.IsZero() {
+		t.Error("Generated UID must not be zero")
+	}
+	if u0 == u1 {
+		t.Errorf("Generated UIDs must be different, got: %s", u0)
+	}
+}
+
+func TestParseUID128(t *testing.T) {
+	expected := UID128{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF}
+
+	testCases := map[string]string{
+		"Plain":  "00112233445566778899aabbccddeeff",
+		"Upper":  "00112233445566778899AABBCCDDEEFF",
+		"Hyphen": "00112233-4455-6677-8899-aabbccddeeff",
+	}
+	for name, s := range testCases {
+		t.Run(name, func(t *testing.T) {
+			u, err := ParseUID128(s)
+			if err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			if u != expected {
+				t.Errorf("Expected UID: %s, got: %s", expected, u)
+			}
+		})
+	}
+
+	if s := expected.String(); s != testCases["Plain"] {
+		t.Errorf("Expected string: %s, got: %s", testCases["Plain"], s)
+	}
+}
+
+func TestParseUID128_Error(t *testing.T) {
+	testCases := map[string]string{
+		"Short":  "0011",
+		"Long":   "00112233445566778899aabbccddeeff00",
+		"NonHex": "0011223344556677889gaabbccddeeff",
+	}
+	for name, s := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseUID128(s); !errs.Is(err, ErrInvalidUID) {
+				t.Errorf("Expected error: '%v', got: '%v'", ErrInvalidUID, err)
+			}
+		})
+	}
+}
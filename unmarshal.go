@@ -16,6 +16,7 @@ package ebml
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"reflect"
@@ -36,37 +37,68 @@ var ErrInvalidElementSize = errors.New("invalid element size")
 // ErrReadStopped is returned if unmarshaler finished to read element which has stop tag.
 var ErrReadStopped = errors.New("read stopped")
 
+// ErrDuplicateElement means that an element stored to a single value field appeared twice in strict mode.
+var ErrDuplicateElement = errors.New("duplicate element")
+
+// ErrUnmappedElement means that an element has no destination field in strict mode.
+var ErrUnmappedElement = errors.New("unmapped element")
+
 // Unmarshal EBML stream.
+//
+// If r is seekable and doesn't implement io.ByteReader, r is internally
+// buffered to reduce the number of read calls, and the bytes read ahead
+// are given back by seeking r before returning.
+// Other readers are read without read-ahead so that the following reads
+// can continue from the end of the read elements.
+// Wrap r by bufio.Reader or use Decoder to reduce the number of read calls
+// on such readers. Decoder also supports resuming the read stopped by the stop tag.
+//
+// Elements without destination field are skipped by seeking r
+// if r implements io.Seeker.
+//
+// time.Duration field is scaled in the same way as Marshal.
+// TimecodeScale of the Segment is taken from the element read before.
+//
+// Metadata of the master element can be stored to the special fields of
+// the struct which the element is unmarshalled to:
+//
+//	// Absolute offset of the element header.
+//	Offset int64 `ebml:",offset"`
+//	// Absolute offset of the element data.
+//	DataOffset int64 `ebml:",dataoffset"`
+//	// Size of the element data.
+//	Size uint64 `ebml:",size"`
+//	// Binary of the whole element including the header.
+//	Raw []byte `ebml:",raw"`
+//
+// Metadata fields are ignored by Marshal.
 func Unmarshal(r io.Reader, val interface{}, opts ...UnmarshalOption) error {
-	options := &UnmarshalOptions{}
-	for _, o := range opts {
-		if err := o(options); err != nil {
-			return err
-		}
-	}
+	return UnmarshalContext(context.Background(), r, val, opts...)
+}
 
-	vo := reflect.ValueOf(val)
-	if !vo.IsValid() {
-		return wrapErrorf(ErrIndefiniteType, "unmarshalling to %T", val)
-	}
-	if vo.Kind() != reflect.Ptr {
-		return wrapErrorf(ErrIncompatibleType, "unmarshalling to %T", val)
+// UnmarshalBytes unmarshals EBML binary.
+//
+// Binary element values are decoded as sub-slices of b without copying.
+// Modifying b after unmarshalling affects the decoded values.
+func UnmarshalBytes(b []byte, val interface{}, opts ...UnmarshalOption) error {
+	options, err := newUnmarshalOptions(opts)
+	if err != nil {
+		return err
 	}
-
-	vd := &valueDecoder{}
-
-	voe := vo.Elem()
-	for {
-		if _, err := vd.readElement(r, SizeUnknown, voe, 0, 0, nil, options); err != nil {
-			if err == io.EOF {
-				return nil
-			}
-			return err
-		}
+	d := newDecoder(&byteSliceReader{b: b}, options)
+	d.vd.readerAt = bytes.NewReader(b)
+	if err := d.Decode(val); err != io.EOF {
+		return err
 	}
+	return nil
 }
 
-func (vd *valueDecoder) readElement(r0 io.Reader, n int64, vo reflect.Value, depth int, pos uint64, parent *Element, options *UnmarshalOptions) (io.Reader, error) {
+// readElement reads elements to vo and returns the position of the end of the read elements.
+// If a top level element is found in the nested element with unknown size,
+// the header of the element is returned with io.EOF to read it by the parent.
+// If the read is stopped by the stop tag, the header of the next element
+// read ahead is returned with ErrReadStopped.
+func (vd *valueDecoder) readElement(r0 io.Reader, n int64, vo reflect.Value, depth int, pos uint64, parent *Element, options *UnmarshalOptions) ([]byte, uint64, error) {
 	pos0 := pos
 	var r rollbackReader
 	if options.ignoreUnknown {
@@ -75,46 +107,45 @@ func (vd *valueDecoder) readElement(r0 io.Reader, n int64, vo reflect.Value, dep
 		r = &rollbackReaderNop{}
 	}
 	if n != SizeUnknown {
-		r.Set(io.LimitReader(r0, n))
+		r.Set(&limitedReader{r: r0, n: n})
 	} else {
 		r.Set(r0)
 	}
 
 	var mapOut bool
-	type fieldDef struct {
-		v    reflect.Value
-		stop bool
-	}
-	fieldMap := make(map[ElementType]fieldDef)
-	switch vo.Kind() {
-	case reflect.Struct:
-		for i := 0; i < vo.NumField(); i++ {
-			f := fieldDef{
-				v: vo.Field(i),
-			}
-			var name string
-			if n, ok := vo.Type().Field(i).Tag.Lookup("ebml"); ok {
-				t, err := parseTag(n)
-				if err != nil {
-					return nil, err
-				}
-				name = t.name
-				f.stop = t.stop
-			}
-			if name == "" {
-				name = vo.Type().Field(i).Name
-			}
-			t, err := ElementTypeFromString(name)
-			if err != nil {
-				return nil, err
-			}
-			fieldMap[t] = f
+	var orderedOut *OrderedMap
+	var si *structInfo
+	switch {
+	case vo.IsValid() && vo.Type() == orderedMapType && vo.CanAddr():
+		orderedOut = vo.Addr().Interface().(*OrderedMap)
+	case vo.Kind() == reflect.Struct:
+		var err error
+		if si, err = getStructInfo(vo.Type()); err != nil {
+			return nil, pos, err
 		}
-	case reflect.Map:
+	case vo.Kind() == reflect.Map:
 		mapOut = true
 	}
 
+	// counts is the number of the elements read for each type to locate errors.
+	counts := make(map[ElementType]int)
+
+	// trailing returns true if the bytes at the end of the known-size element
+	// can't be read as a child element in strict mode.
+	trailing := func(err error) bool {
+		return options.strict && n != SizeUnknown && err != nil
+	}
+	newTrailingError := func(pos uint64) error {
+		return &ElementError{
+			Err:      wrapErrorf(ErrInvalidElementSize, "%d trailing bytes", pos0+uint64(n)-pos),
+			Position: pos,
+		}
+	}
+
 	for {
+		if err := contextError(vd.ctx); err != nil {
+			return nil, pos, err
+		}
 		r.Reset()
 
 		var headerSize uint64
@@ -122,12 +153,15 @@ func (vd *valueDecoder) readElement(r0 io.Reader, n int64, vo reflect.Value, dep
 		headerSize += uint64(nb)
 		if err != nil {
 			if nb == 0 && err == io.ErrUnexpectedEOF {
-				return nil, io.EOF
+				return nil, pos, io.EOF
+			}
+			if trailing(err) {
+				return nil, pos, newTrailingError(pos)
 			}
 			if options.ignoreUnknown {
-				return nil, nil
+				return nil, pos, nil
 			}
-			return nil, err
+			return nil, pos, &ElementError{Err: err, Position: pos}
 		}
 		v, ok := revTable[uint32(e)]
 		if !ok {
@@ -136,12 +170,40 @@ func (vd *valueDecoder) readElement(r0 io.Reader, n int64, vo reflect.Value, dep
 				pos++
 				continue
 			}
-			return nil, wrapErrorf(ErrUnknownElement, "unmarshalling element 0x%x", e)
+			return nil, pos, &ElementError{
+				Err:      wrapErrorf(ErrUnknownElement, "unmarshalling element 0x%x", e),
+				Position: pos,
+				ID:       uint32(e) | 1<<uint(7*nb),
+			}
+		}
+
+		var vnext reflect.Value
+		var tag *structTag
+		if si != nil {
+			if f, ok := si.byType[v.e]; ok {
+				vnext = vo.Field(f.index)
+				tag = f.tag
+			}
+		}
+		stopHere := tag != nil && tag.stop
+
+		repeated := isRepeatedDest(vnext, v.t == DataTypeBinary)
+		newError := func(err error) error {
+			return &ElementError{
+				Err:      err,
+				Path:     elementPathName(v.e, counts[v.e], repeated),
+				Position: pos,
+				ID:       elementID(table[v.e].b),
+				Type:     v.e,
+			}
 		}
 
 		size, nb, err := vd.readDataSize(r)
 		headerSize += uint64(nb)
 
+		if trailing(err) {
+			return nil, pos, newTrailingError(pos)
+		}
 		if n != SizeUnknown && pos+headerSize+size > pos0+uint64(n) {
 			err = ErrInvalidElementSize
 		}
@@ -152,27 +214,51 @@ func (vd *valueDecoder) readElement(r0 io.Reader, n int64, vo reflect.Value, dep
 				pos++
 				continue
 			}
-			return nil, err
+			return nil, pos, newError(err)
 		}
 
-		var vnext reflect.Value
-		var stopHere bool
-		if vn, ok := fieldMap[v.e]; ok {
-			if !mapOut {
-				vnext = vn.v
+		if options.strict && si != nil && !(v.t == DataTypeMaster && v.top && depth > 1) {
+			switch {
+			case !vnext.IsValid():
+				if v.e != ElementVoid && v.e != ElementCRC32 {
+					return nil, pos, newError(wrapErrorf(
+						ErrUnmappedElement, "unmarshalling %s to %s", v.e, vo.Type(),
+					))
+				}
+			case !repeated && counts[v.e] > 0:
+				return nil, pos, newError(wrapErrorf(
+					ErrDuplicateElement, "unmarshalling %s to %s", v.e, vo.Type(),
+				))
 			}
-			stopHere = vn.stop
 		}
 
+		newIncompatibleError := func(expected, actual reflect.Type) error {
+			err := newError(wrapErrorf(
+				ErrIncompatibleType, "unmarshalling %s to %s", expected, actual,
+			)).(*ElementError)
+			err.Expected, err.Actual = expected, actual
+			return err
+		}
+
+		newOverflowError := func(t reflect.Type, v reflect.Value) error {
+			return newError(wrapErrorf(
+				ErrOutOfRange, "unmarshalling %v to %s", v.Interface(), t,
+			))
+		}
+
+		// pending is the header of the next element read ahead.
+		var pending []byte
 		var chanSend reflect.Value
 		var elem *Element
 		if len(options.hooks) > 0 && vnext.IsValid() {
 			elem = &Element{
-				Name:     v.e.String(),
-				Type:     v.e,
-				Position: pos,
-				Size:     size,
-				Parent:   parent,
+				Name:         v.e.String(),
+				Type:         v.e,
+				Position:     pos,
+				Size:         size,
+				HeaderSize:   headerSize,
+				DataPosition: pos + headerSize,
+				Parent:       parent,
 			}
 		}
 		if vnext.Kind() == reflect.Chan {
@@ -180,17 +266,43 @@ func (vd *valueDecoder) readElement(r0 io.Reader, n int64, vo reflect.Value, dep
 			vnext = reflect.New(vnext.Type().Elem()).Elem()
 		}
 
-		switch v.t {
-		case DataTypeMaster:
+		// Element without destination is skipped without reading the payload.
+		// TimecodeScale and Info are always read since TimecodeScale is
+		// required to scale time.Duration fields.
+		// Binary and Block elements are also skipped in scan mode
+		// unless the destination is a lazy binary.
+		skippable := !mapOut && orderedOut == nil &&
+			size != SizeUnknown && !(v.top && depth > 1) &&
+			((!vnext.IsValid() && v.e != ElementTimecodeScale && v.e != ElementInfo) ||
+				(options.skipBinary && (v.t == DataTypeBinary || v.t == DataTypeBlock) &&
+					lazyBinaryDest(vnext) == nil))
+
+		switch {
+		case skippable:
+			if err := skip(r, size); err != nil {
+				if options.ignoreUnknown {
+					r.RollbackTo(1)
+					pos++
+					continue
+				}
+				return nil, pos, newError(err)
+			}
+		case v.t == DataTypeMaster:
+			if v.e == ElementSegment {
+				vd.timecodeScale = 0
+			}
 			if v.top && depth > 1 {
-				b := bytes.Join([][]byte{table[v.e].b, encodeDataSize(size, uint64(nb))}, []byte{})
-				return bytes.NewBuffer(b), io.EOF
+				return elementHeader(v.e, size, nb), pos, io.EOF
 			}
 			var vn reflect.Value
-			if mapOut {
+			switch {
+			case mapOut:
 				vnext = reflect.ValueOf(make(map[string]interface{}))
 				vn = vnext
-			} else {
+			case orderedOut != nil:
+				vnext = reflect.ValueOf(&OrderedMap{})
+				vn = vnext.Elem()
+			default:
 				if vnext.IsValid() && vnext.CanSet() {
 					switch vnext.Kind() {
 					case reflect.Ptr:
@@ -205,27 +317,96 @@ func (vd *valueDecoder) readElement(r0 io.Reader, n int64, vo reflect.Value, dep
 				}
 			}
 			if elem != nil {
-				elem.Value = vn.Interface()
+				if orderedOut != nil {
+					elem.Value = vnext.Interface()
+				} else {
+					elem.Value = vn.Interface()
+				}
+			}
+			var meta *structInfo
+			if vn.IsValid() && vn.Kind() == reflect.Struct {
+				if si, err := getStructInfo(vn.Type()); err == nil && si.meta != nil {
+					meta = si
+				}
+			}
+			var rc io.Reader = r
+			var capture *captureReader
+			var header []byte
+			if meta != nil {
+				if _, ok := meta.meta[metaRaw]; ok {
+					capture = &captureReader{r: r}
+					rc = capture
+					header = elementHeader(v.e, size, nb)
+				}
+			}
+			head, end, err := vd.readElement(rc, int64(size), vn, depth+1, pos+headerSize, elem, options)
+			if err == ErrReadStopped || isContextError(vd.ctx, err) {
+				return head, end, err
 			}
-			r0, err := vd.readElement(r, int64(size), vn, depth+1, pos+headerSize, elem, options)
 			if err != nil && err != io.EOF {
-				return r0, err
+				return head, pos, withParent(err, elementPathName(v.e, counts[v.e], repeated))
 			}
-			if r0 != nil {
-				r.Set(io.MultiReader(r0, r.Get()))
+			if size == SizeUnknown {
+				size = end - pos - headerSize
+			} else if options.strict && end != pos+headerSize+size {
+				return nil, pos, newError(wrapErrorf(
+					ErrInvalidElementSize, "%d trailing bytes", pos+headerSize+size-end,
+				))
+			}
+			if meta != nil {
+				var raw []byte
+				if capture != nil && uint64(len(capture.buf)) >= size {
+					raw = append(header, capture.buf[:size]...)
+				}
+				meta.setMeta(vn, pos, headerSize, size, raw)
+			}
+			if head != nil {
+				r.Set(&prefixedReader{head: head, r: r.Get()})
+				pending = head
 			}
 		default:
-			val, err := vd.decode(v.t, r, size)
-			if err != nil {
-				if options.ignoreUnknown {
-					r.RollbackTo(1)
-					pos++
-					continue
+			var vr reflect.Value
+			if lt := lazyBinaryDest(vnext); v.t == DataTypeBinary && lt != nil {
+				lb, err := vd.readLazyBinary(r, pos+headerSize, size)
+				if err != nil {
+					if options.ignoreUnknown {
+						r.RollbackTo(1)
+						pos++
+						continue
+					}
+					return nil, pos, newError(err)
+				}
+				vr = lazyBinaryValue(lb, lt)
+			} else {
+				val, err := vd.decode(v.t, r, size)
+				if err != nil {
+					if options.ignoreUnknown {
+						r.RollbackTo(1)
+						pos++
+						continue
+					}
+					return nil, pos, newError(err)
+				}
+				if v.e == ElementTimecodeScale {
+					vd.timecodeScale = val.(uint64)
+				}
+				vr = reflect.ValueOf(val)
+				if isDurationDest(vnext) && !mapOut && orderedOut == nil {
+					d, err := decodeDuration(val, durationScale(tag, v.e, vd.timecodeScale))
+					if err != nil {
+						return nil, pos, newError(err)
+					}
+					vr = reflect.ValueOf(d)
+				}
+				if b, ok := val.([]byte); ok && !mapOut && orderedOut == nil && vnext.IsValid() {
+					if at := byteArrayDest(vnext.Type()); at != nil {
+						if vr, err = bytesToArray(b, at); err != nil {
+							return nil, pos, newError(err)
+						}
+					}
 				}
-				return nil, err
 			}
-			vr := reflect.ValueOf(val)
-			if mapOut {
+			if mapOut || orderedOut != nil {
 				vnext = vr
 			} else {
 				if vnext.IsValid() && vnext.CanSet() {
@@ -233,6 +414,9 @@ func (vd *valueDecoder) readElement(r0 io.Reader, n int64, vo reflect.Value, dep
 					case vr.Type() == vnext.Type():
 						vnext.Set(vr)
 					case isConvertible(vr.Type(), vnext.Type()):
+						if options.strict && overflows(vr, vnext.Type()) {
+							return nil, pos, newOverflowError(vnext.Type(), vr)
+						}
 						vnext.Set(vr.Convert(vnext.Type()))
 					case vnext.Kind() == reflect.Slice:
 						t := vnext.Type().Elem()
@@ -240,16 +424,15 @@ func (vd *valueDecoder) readElement(r0 io.Reader, n int64, vo reflect.Value, dep
 						case vr.Type() == t:
 							vnext.Set(reflect.Append(vnext, vr))
 						case isConvertible(vr.Type(), t):
+							if options.strict && overflows(vr, t) {
+								return nil, pos, newOverflowError(t, vr)
+							}
 							vnext.Set(reflect.Append(vnext, vr.Convert(t)))
 						default:
-							return nil, wrapErrorf(
-								ErrIncompatibleType, "unmarshalling %s to %s", vnext.Type(), vr.Type(),
-							)
+							return nil, pos, newIncompatibleError(vnext.Type(), vr.Type())
 						}
 					default:
-						return nil, wrapErrorf(
-							ErrIncompatibleType, "unmarshalling %s to %s", vnext.Type(), vr.Type(),
-						)
+						return nil, pos, newIncompatibleError(vnext.Type(), vr.Type())
 					}
 				}
 			}
@@ -276,8 +459,13 @@ func (vd *valueDecoder) readElement(r0 io.Reader, n int64, vo reflect.Value, dep
 			}
 			vo.SetMapIndex(key, vnext)
 		}
-		if chanSend.IsValid() {
-			chanSend.Send(vnext)
+		if orderedOut != nil {
+			orderedOut.Add(v.e.String(), vnext.Interface())
+		}
+		if chanSend.IsValid() && !skippable {
+			if err := sendContext(vd.ctx, chanSend, vnext); err != nil {
+				return nil, pos, err
+			}
 		}
 		if elem != nil {
 			for _, hook := range options.hooks {
@@ -285,9 +473,11 @@ func (vd *valueDecoder) readElement(r0 io.Reader, n int64, vo reflect.Value, dep
 			}
 		}
 
+		counts[v.e]++
 		pos += headerSize + size
 		if stopHere {
-			return nil, ErrReadStopped
+			vd.stopDepth = depth
+			return pending, pos, ErrReadStopped
 		}
 	}
 }
@@ -295,10 +485,22 @@ func (vd *valueDecoder) readElement(r0 io.Reader, n int64, vo reflect.Value, dep
 // UnmarshalOption configures a UnmarshalOptions struct.
 type UnmarshalOption func(*UnmarshalOptions) error
 
+func newUnmarshalOptions(opts []UnmarshalOption) (*UnmarshalOptions, error) {
+	options := &UnmarshalOptions{}
+	for _, o := range opts {
+		if err := o(options); err != nil {
+			return nil, err
+		}
+	}
+	return options, nil
+}
+
 // UnmarshalOptions stores options for unmarshalling.
 type UnmarshalOptions struct {
 	hooks         []func(elem *Element)
 	ignoreUnknown bool
+	strict        bool
+	skipBinary    bool
 }
 
 // WithElementReadHooks returns an UnmarshalOption which registers element hooks.
@@ -316,3 +518,34 @@ func WithIgnoreUnknown(ignore bool) UnmarshalOption {
 		return nil
 	}
 }
+
+// WithStrict returns an UnmarshalOption which makes Unmarshal rejecting
+// ambiguous or lossy input.
+// In strict mode, following cases are treated as errors:
+//   - an element stored to a non-slice field appears twice (ErrDuplicateElement)
+//   - an element has no destination struct field (ErrUnmappedElement),
+//     except Void and CRC-32 elements
+//   - a master element with known size has trailing bytes not consumed by the children (ErrInvalidElementSize)
+//   - a variable length integer starts with zero byte (ErrInvalidVINT)
+//   - a value overflows the destination type like 256 to uint8 (ErrOutOfRange)
+func WithStrict() UnmarshalOption {
+	return func(opts *UnmarshalOptions) error {
+		opts.strict = true
+		return nil
+	}
+}
+
+// WithSkipBinary returns an UnmarshalOption which enables scan mode.
+// In scan mode, payloads of Binary and Block elements like SimpleBlock and
+// CodecPrivate are not read, and the destination fields are left unchanged.
+// The payloads are skipped by seeking the source if it implements io.Seeker.
+// Destination fields of LazyBinary types still receive LazyBinary
+// referring the source.
+// Value of the Element passed to the hooks is nil for the skipped elements.
+// It makes metadata-only reads of large files fast.
+func WithSkipBinary() UnmarshalOption {
+	return func(opts *UnmarshalOptions) error {
+		opts.skipBinary = true
+		return nil
+	}
+}
@@ -221,6 +221,28 @@ func TestUnmarshal_Convert(t *testing.T) {
 				Duration []float32 `ebml:"Duration"`
 			}{[]float32{0.0}},
 		},
+		"BinaryToByteArray": {
+			[]byte{0x53, 0xAB, 0x82, 0x01, 0x02},
+			struct {
+				SeekID [2]byte `ebml:"SeekID"`
+			}{[2]byte{0x01, 0x02}},
+		},
+		"BinaryToByteArraySlice": {
+			[]byte{0x53, 0xAB, 0x82, 0x01, 0x02, 0x53, 0xAB, 0x82, 0x03, 0x04},
+			struct {
+				SeekID [][2]byte `ebml:"SeekID"`
+			}{[][2]byte{{0x01, 0x02}, {0x03, 0x04}}},
+		},
+		"BinaryToUID128": {
+			[]byte{
+				0x73, 0xA4, 0x90,
+				0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
+				0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F,
+			},
+			struct {
+				SegmentUID UID128 `ebml:"SegmentUID"`
+			}{UID128{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F}},
+		},
 	}
 
 	for name, c := range cases {
@@ -311,6 +333,60 @@ func TestUnmarshal_WithElementReadHooks(t *testing.T) {
 		case v != "Video":
 			t.Errorf("The value should be Video, got %s", v)
 		}
+
+		expectedHeader := map[string]struct{ headerSize, dataPos uint64 }{
+			"Segment":                        {5, 5},
+			"Segment.Tracks":                 {5, 15},
+			"Segment.Tracks.TrackEntry":      {2, 17},
+			"Segment.Tracks.TrackEntry.Name": {3, 20},
+		}
+		for key, exp := range expectedHeader {
+			e := m[key][0]
+			if e.HeaderSize != exp.headerSize || e.DataPosition != exp.dataPos {
+				t.Errorf("Unexpected header of %s, expected: (size %d, data %d), got: (size %d, data %d)",
+					key, exp.headerSize, exp.dataPos, e.HeaderSize, e.DataPosition)
+			}
+		}
+	})
+}
+
+func TestUnmarshal_WithElementReadHooks_UnknownSize(t *testing.T) {
+	testBinary := []byte{
+		0x18, 0x53, 0x80, 0x67, 0xFF, // Segment
+		0x1F, 0x43, 0xB6, 0x75, 0xFF, // Cluster
+		0xE7, 0x81, 0x01, // Timecode
+		0x1F, 0x43, 0xB6, 0x75, 0xFF, // Cluster
+		0xE7, 0x81, 0x02, // Timecode
+		0x1C, 0x53, 0xBB, 0x6B, 0x80, // Cues (empty)
+	}
+
+	type TestEBML struct {
+		Segment struct {
+			Cluster []struct {
+				Timecode uint64
+			} `ebml:"Cluster,size=unknown"`
+			Cues struct{}
+		} `ebml:"Segment,size=unknown"`
+	}
+
+	runForEachReader(t, testBinary, func(t *testing.T, r func() io.Reader) {
+		var ret TestEBML
+		m := make(map[string][]*Element)
+		hook := withElementMap(m)
+		if err := Unmarshal(r(), &ret, WithElementReadHooks(hook)); err != nil {
+			t.Errorf("Unexpected error: '%v'", err)
+		}
+
+		expected := map[string][]uint64{
+			"Segment":                   {0},
+			"Segment.Cluster":           {5, 13},
+			"Segment.Cluster.Timestamp": {10, 18},
+			"Segment.Cues":              {21},
+		}
+		posMap := elementPositionMap(m)
+		if !reflect.DeepEqual(expected, posMap) {
+			t.Errorf("Unexpected read hook positions, \nexpected: %v, \n     got: %v", expected, posMap)
+		}
 	})
 }
 
@@ -632,6 +708,20 @@ func TestUnmarshal_Error(t *testing.T) {
 				}{},
 				err: ErrIncompatibleType,
 			},
+			"ShortBinaryToByteArray": {
+				b: []byte{0x53, 0xAB, 0x81, 0x01},
+				ret: &struct {
+					SeekID [2]byte `ebml:"SeekID"`
+				}{},
+				err: ErrInvalidElementSize,
+			},
+			"LongBinaryToByteArraySlice": {
+				b: []byte{0x53, 0xAB, 0x83, 0x01, 0x02, 0x03},
+				ret: &struct {
+					SeekID [][2]byte `ebml:"SeekID"`
+				}{},
+				err: ErrInvalidElementSize,
+			},
 		}
 		for name, c := range cases {
 			t.Run(name, func(t *testing.T) {
@@ -688,6 +778,130 @@ func ExampleUnmarshal_partial() {
 	// Second unmarshal: {[{0 [{1 0 true true 0 false [[170 204]]}]}]}
 }
 
+func TestUnmarshalBytes(t *testing.T) {
+	b := []byte{
+		0x1A, 0x45, 0xDF, 0xA3, 0x8B,
+		0x42, 0x82, 0x84, 0x77, 0x65, 0x62, 0x6D, // EBMLDocType = webm
+		0x53, 0xAB, 0x81, 0x01, // SeekID = 0x01
+	}
+	var ret struct {
+		Header struct {
+			DocType string `ebml:"EBMLDocType"`
+			SeekID  []byte `ebml:"SeekID"`
+		} `ebml:"EBML"`
+	}
+	if err := UnmarshalBytes(b, &ret); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if ret.Header.DocType != "webm" {
+		t.Errorf("Expected DocType: webm, got: %s", ret.Header.DocType)
+	}
+	if !bytes.Equal([]byte{0x01}, ret.Header.SeekID) {
+		t.Fatalf("Expected SeekID: [1], got: %v", ret.Header.SeekID)
+	}
+
+	// Binary must refer the input without copying.
+	b[15] = 0x02
+	if ret.Header.SeekID[0] != 0x02 {
+		t.Error("Binary element is copied")
+	}
+	if cap(ret.Header.SeekID) != 1 {
+		t.Errorf("Capacity of the binary must be limited to the element size, got: %d", cap(ret.Header.SeekID))
+	}
+}
+
+type readSeekerOnly struct {
+	r *bytes.Reader
+}
+
+func (r *readSeekerOnly) Read(b []byte) (int, error) {
+	return r.r.Read(b)
+}
+
+func (r *readSeekerOnly) Seek(offset int64, whence int) (int64, error) {
+	return r.r.Seek(offset, whence)
+}
+
+func TestUnmarshal_StopAndResumeSeeker(t *testing.T) {
+	b := []byte{
+		0x18, 0x53, 0x80, 0x67, 0xFF, // Segment
+		0x16, 0x54, 0xAE, 0x6B, 0x85, // Tracks
+		0xAE, 0x83, // TrackEntry[0]
+		0xD7, 0x81, 0x01, // TrackNumber=1
+		0x1F, 0x43, 0xB6, 0x75, 0xFF, // Cluster
+		0xE7, 0x81, 0x05, // Timecode
+	}
+	r := &readSeekerOnly{r: bytes.NewReader(b)}
+
+	var header struct {
+		Segment struct {
+			Tracks struct{} `ebml:"Tracks,stop"`
+		}
+	}
+	if err := Unmarshal(r, &header); !errs.Is(err, ErrReadStopped) {
+		t.Fatalf("Expected error: '%v', got: '%v'", ErrReadStopped, err)
+	}
+	if pos, _ := r.Seek(0, io.SeekCurrent); pos != 15 {
+		t.Fatalf("Reader must be positioned after the stopped element (15), got: %d", pos)
+	}
+
+	var clusters struct {
+		Cluster struct {
+			Timecode uint64
+		}
+	}
+	if err := Unmarshal(r, &clusters); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if clusters.Cluster.Timecode != 5 {
+		t.Errorf("Expected Timecode: 5, got: %d", clusters.Cluster.Timecode)
+	}
+}
+
+func TestUnmarshal_StopAndResumePipe(t *testing.T) {
+	b := []byte{
+		0x18, 0x53, 0x80, 0x67, 0xFF, // Segment
+		0x16, 0x54, 0xAE, 0x6B, 0x85, // Tracks
+		0xAE, 0x83, // TrackEntry[0]
+		0xD7, 0x81, 0x01, // TrackNumber=1
+		0x1F, 0x43, 0xB6, 0x75, 0xFF, // Cluster
+		0xE7, 0x81, 0x05, // Timecode
+	}
+	r, w := io.Pipe()
+	go func() {
+		_, _ = w.Write(b)
+		_ = w.Close()
+	}()
+
+	var header struct {
+		Segment struct {
+			Tracks struct {
+				TrackEntry struct {
+					TrackNumber uint64
+				}
+			} `ebml:"Tracks,stop"`
+		}
+	}
+	if err := Unmarshal(r, &header); !errs.Is(err, ErrReadStopped) {
+		t.Fatalf("Expected error: '%v', got: '%v'", ErrReadStopped, err)
+	}
+	if n := header.Segment.Tracks.TrackEntry.TrackNumber; n != 1 {
+		t.Errorf("Expected TrackNumber: 1, got: %d", n)
+	}
+
+	var clusters struct {
+		Cluster struct {
+			Timecode uint64
+		}
+	}
+	if err := Unmarshal(r, &clusters); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if clusters.Cluster.Timecode != 5 {
+		t.Errorf("Expected Timecode: 5, got: %d", clusters.Cluster.Timecode)
+	}
+}
+
 func BenchmarkUnmarshal(b *testing.B) {
 	TestBinary := []byte{
 		0x1a, 0x45, 0xdf, 0xa3, // EBML
@@ -730,3 +944,505 @@ func BenchmarkUnmarshal(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkUnmarshalBytes(b *testing.B) {
+	TestBinary := []byte{
+		0x1a, 0x45, 0xdf, 0xa3, // EBML
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, // 0x10
+		0x42, 0x82, 0x85, 0x77, 0x65, 0x62, 0x6d, 0x00, // DocType = webm
+		0x42, 0x87, 0x81, 0x02, // DocTypeVersion = 2
+		0x42, 0x85, 0x81, 0x02, // DocTypeReadVersion = 2
+		0x18, 0x53, 0x80, 0x67, 0xFF, // Segment
+		0x1F, 0x43, 0xB6, 0x75, 0xFF, // Cluster
+		0xE7, 0x81, 0x00, // Timecode
+		0xA3, 0x86, 0x81, 0x00, 0x00, 0x88, 0xAA, 0xCC, // SimpleBlock
+		0xA3, 0x86, 0x81, 0x00, 0x10, 0x88, 0xAA, 0xCC, // SimpleBlock
+		0xA3, 0x86, 0x81, 0x00, 0x20, 0x88, 0xAA, 0xCC, // SimpleBlock
+	}
+	type TestEBML struct {
+		Header struct {
+			DocType            string `ebml:"EBMLDocType"`
+			DocTypeVersion     uint64 `ebml:"EBMLDocTypeVersion"`
+			DocTypeReadVersion uint64 `ebml:"EBMLDocTypeReadVersion"`
+		} `ebml:"EBML"`
+		Segment []struct {
+			Cluster struct {
+				Timecode    uint64
+				SimpleBlock []Block
+			}
+		}
+	}
+
+	var ret TestEBML
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := UnmarshalBytes(TestBinary, &ret); err != nil {
+			b.Fatalf("Unexpected error: '%v'", err)
+		}
+	}
+}
+
+func TestUnmarshal_ElementError(t *testing.T) {
+	b := []byte{
+		0x18, 0x53, 0x80, 0x67, 0x93, // Segment
+		0x1F, 0x43, 0xB6, 0x75, 0x83, // Cluster
+		0xE7, 0x81, 0x00, // Timecode
+		0x1F, 0x43, 0xB6, 0x75, 0x86, // Cluster
+		0xE7, 0x81, 0x01, // Timecode
+		0xA7, 0x81, 0x01, // Position
+	}
+	var ret struct {
+		Segment struct {
+			Cluster []struct {
+				Timecode uint64 `ebml:"Timecode"`
+				Position string `ebml:"Position"`
+			} `ebml:"Cluster"`
+		} `ebml:"Segment"`
+	}
+	runForEachReader(t, b, func(t *testing.T, r func() io.Reader) {
+		err := Unmarshal(r(), &ret)
+		if !errs.Is(err, ErrIncompatibleType) {
+			t.Fatalf("Expected error: '%v', got: '%v'", ErrIncompatibleType, err)
+		}
+		elemErr, ok := err.(*ElementError)
+		if !ok {
+			t.Fatalf("Expected *ElementError, got: %T", err)
+		}
+		expected := &ElementError{
+			Err:      elemErr.Err,
+			Path:     "Segment/Cluster[1]/Position",
+			Position: 21,
+			ID:       0xA7,
+			Type:     ElementPosition,
+			Expected: reflect.TypeOf(""),
+			Actual:   reflect.TypeOf(uint64(0)),
+		}
+		if !reflect.DeepEqual(expected, elemErr) {
+			t.Errorf("Expected error: %+v, got: %+v", expected, elemErr)
+		}
+	})
+}
+
+func TestUnmarshal_Strict(t *testing.T) {
+	type header struct {
+		DocType        string `ebml:"EBMLDocType"`
+		DocTypeVersion uint8  `ebml:"EBMLDocTypeVersion"`
+	}
+	type blockGroup struct {
+		ReferenceBlock []int8 `ebml:"ReferenceBlock"`
+	}
+	type headerFloat struct {
+		Duration float32 `ebml:"Duration"`
+	}
+	cases := map[string]struct {
+		b   []byte
+		ret interface{}
+		err error
+	}{
+		"Valid": {
+			b: []byte{
+				0x1A, 0x45, 0xDF, 0xA3, 0x8A,
+				0x42, 0x82, 0x81, 0x61,
+				0x42, 0x87, 0x81, 0x02,
+				0xEC, 0x80, // Void
+			},
+			ret: &struct {
+				EBML header `ebml:"EBML"`
+			}{},
+		},
+		"Duplicate": {
+			b: []byte{
+				0x1A, 0x45, 0xDF, 0xA3, 0x88,
+				0x42, 0x87, 0x81, 0x02,
+				0x42, 0x87, 0x81, 0x03,
+			},
+			ret: &struct {
+				EBML header `ebml:"EBML"`
+			}{},
+			err: ErrDuplicateElement,
+		},
+		"Unmapped": {
+			b: []byte{
+				0x1A, 0x45, 0xDF, 0xA3, 0x84,
+				0x42, 0xF7, 0x81, 0x01, // EBMLReadVersion
+			},
+			ret: &struct {
+				EBML header `ebml:"EBML"`
+			}{},
+			err: ErrUnmappedElement,
+		},
+		"ZeroPadding": {
+			b: []byte{
+				0x1A, 0x45, 0xDF, 0xA3, 0x86,
+				0x42, 0x87, 0x81, 0x02,
+				0x00, 0x00,
+			},
+			ret: &struct {
+				EBML header `ebml:"EBML"`
+			}{},
+			err: ErrInvalidElementSize,
+		},
+		"OverflowUInt": {
+			b: []byte{
+				0x1A, 0x45, 0xDF, 0xA3, 0x85,
+				0x42, 0x87, 0x82, 0x01, 0x00,
+			},
+			ret: &struct {
+				EBML header `ebml:"EBML"`
+			}{},
+			err: ErrOutOfRange,
+		},
+		"OverflowIntSlice": {
+			b: []byte{
+				0xFB, 0x81, 0x7F,
+				0xFB, 0x82, 0xFF, 0x7F,
+			},
+			ret: &blockGroup{},
+			err: ErrOutOfRange,
+		},
+		"OverflowFloat": {
+			b: []byte{
+				0x44, 0x89, 0x88, 0x7F, 0xEF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+			},
+			ret: &headerFloat{},
+			err: ErrOutOfRange,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			runForEachReader(t, c.b, func(t *testing.T, r func() io.Reader) {
+				ret := reflect.New(reflect.TypeOf(c.ret).Elem()).Interface()
+				if err := Unmarshal(r(), ret); err != nil {
+					t.Fatalf("Unexpected error in non-strict mode: '%v'", err)
+				}
+				ret = reflect.New(reflect.TypeOf(c.ret).Elem()).Interface()
+				err := Unmarshal(r(), ret, WithStrict())
+				if c.err == nil {
+					if err != nil {
+						t.Fatalf("Unexpected error: '%v'", err)
+					}
+					return
+				}
+				if !errs.Is(err, c.err) {
+					t.Fatalf("Expected error: '%v', got: '%v'", c.err, err)
+				}
+			})
+		})
+	}
+}
+
+func TestUnmarshal_StrictTrailingBytes(t *testing.T) {
+	inputs := map[string][]byte{
+		"IncompleteID": {
+			0x1A, 0x45, 0xDF, 0xA3, 0x85,
+			0x42, 0x87, 0x81, 0x02,
+			0x42, // incomplete element ID
+		},
+		"IncompleteSize": {
+			0x1A, 0x45, 0xDF, 0xA3, 0x86,
+			0x42, 0x87, 0x81, 0x02,
+			0x42, 0x87, // no data size
+		},
+		"InvalidVINT": {
+			0x1A, 0x45, 0xDF, 0xA3, 0x85,
+			0x42, 0x87, 0x81, 0x02,
+			0x00,
+		},
+	}
+	testCases := map[string][]UnmarshalOption{
+		"Strict":              {WithStrict()},
+		"StrictIgnoreUnknown": {WithIgnoreUnknown(true), WithStrict()},
+	}
+	for name, b := range inputs {
+		b := b
+		t.Run(name, func(t *testing.T) {
+			for name, opts := range testCases {
+				opts := opts
+				t.Run(name, func(t *testing.T) {
+					var ret struct {
+						EBML struct {
+							DocTypeVersion uint64 `ebml:"EBMLDocTypeVersion"`
+						} `ebml:"EBML"`
+					}
+					runForEachReader(t, b, func(t *testing.T, r func() io.Reader) {
+						if err := Unmarshal(r(), &ret, WithIgnoreUnknown(true)); err != nil {
+							t.Fatalf("Unexpected error in non-strict mode: '%v'", err)
+						}
+						err := Unmarshal(r(), &ret, opts...)
+						if !errs.Is(err, ErrInvalidElementSize) {
+							t.Fatalf("Expected error: '%v', got: '%v'", ErrInvalidElementSize, err)
+						}
+					})
+				})
+			}
+		})
+	}
+}
+
+type countingReadSeeker struct {
+	rs   io.ReadSeeker
+	read int
+}
+
+func (r *countingReadSeeker) Read(b []byte) (int, error) {
+	n, err := r.rs.Read(b)
+	r.read += n
+	return n, err
+}
+
+func (r *countingReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	return r.rs.Seek(offset, whence)
+}
+
+func TestUnmarshal_SkipUnmapped(t *testing.T) {
+	type cluster struct {
+		Timecode    uint64
+		SimpleBlock []Block
+	}
+	type trackEntry struct {
+		TrackNumber uint64
+	}
+	type tracks struct {
+		TrackEntry []trackEntry
+	}
+	input := struct {
+		Segment struct {
+			Cluster []cluster
+			Tracks  tracks
+		}
+	}{}
+	input.Segment.Cluster = []cluster{
+		{Timecode: 1, SimpleBlock: []Block{{TrackNumber: 3, Data: [][]byte{make([]byte, 0x10000)}}}},
+		{Timecode: 2, SimpleBlock: []Block{{TrackNumber: 3, Data: [][]byte{make([]byte, 0x10000)}}}},
+	}
+	input.Segment.Tracks.TrackEntry = []trackEntry{{TrackNumber: 3}}
+
+	var buf bytes.Buffer
+	if err := Marshal(&input, &buf); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	b := buf.Bytes()
+
+	type result struct {
+		Segment struct {
+			Tracks tracks
+		}
+	}
+	expected := result{}
+	expected.Segment.Tracks = input.Segment.Tracks
+
+	t.Run("Seeker", func(t *testing.T) {
+		r := &countingReadSeeker{rs: bytes.NewReader(b)}
+		var ret result
+		if err := Unmarshal(r, &ret); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		if !reflect.DeepEqual(expected, ret) {
+			t.Errorf("Expected result: %+v, got: %+v", expected, ret)
+		}
+		if r.read > 3*bufferedReaderSize {
+			t.Errorf("Unmapped elements are expected to be skipped by seeking, but read %d bytes", r.read)
+		}
+	})
+	t.Run("NotSeeker", func(t *testing.T) {
+		var ret result
+		if err := Unmarshal(&readerOnly{bytes.NewReader(b)}, &ret); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		if !reflect.DeepEqual(expected, ret) {
+			t.Errorf("Expected result: %+v, got: %+v", expected, ret)
+		}
+	})
+	t.Run("Bytes", func(t *testing.T) {
+		var ret result
+		if err := UnmarshalBytes(b, &ret); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		if !reflect.DeepEqual(expected, ret) {
+			t.Errorf("Expected result: %+v, got: %+v", expected, ret)
+		}
+	})
+	t.Run("UnexpectedEOF", func(t *testing.T) {
+		var ret result
+		r := &countingReadSeeker{rs: bytes.NewReader(b[:0x8000])}
+		if err := Unmarshal(r, &ret); !errs.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Expected error: '%v', got: '%v'", io.ErrUnexpectedEOF, err)
+		}
+	})
+}
+
+func TestUnmarshal_WithSkipBinary(t *testing.T) {
+	type trackEntry struct {
+		TrackNumber  uint64
+		CodecPrivate []byte
+	}
+	type cluster struct {
+		Timecode    uint64
+		SimpleBlock []Block
+	}
+	input := struct {
+		Segment struct {
+			Tracks struct {
+				TrackEntry []trackEntry
+			}
+			Cluster []cluster
+		}
+	}{}
+	input.Segment.Tracks.TrackEntry = []trackEntry{
+		{TrackNumber: 3, CodecPrivate: make([]byte, 0x10000)},
+	}
+	input.Segment.Cluster = []cluster{
+		{Timecode: 1, SimpleBlock: []Block{{TrackNumber: 3, Data: [][]byte{make([]byte, 0x10000)}}}},
+		{Timecode: 2, SimpleBlock: []Block{{TrackNumber: 3, Data: [][]byte{make([]byte, 0x10000)}}}},
+	}
+
+	var buf bytes.Buffer
+	if err := Marshal(&input, &buf); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	b := buf.Bytes()
+
+	t.Run("Seeker", func(t *testing.T) {
+		r := &countingReadSeeker{rs: bytes.NewReader(b)}
+		var ret struct {
+			Segment struct {
+				Tracks struct {
+					TrackEntry []trackEntry
+				}
+				Cluster []cluster
+			}
+		}
+		if err := Unmarshal(r, &ret, WithSkipBinary()); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		expectedTracks := []trackEntry{{TrackNumber: 3}}
+		if !reflect.DeepEqual(expectedTracks, ret.Segment.Tracks.TrackEntry) {
+			t.Errorf("Expected TrackEntry: %+v, got: %+v", expectedTracks, ret.Segment.Tracks.TrackEntry)
+		}
+		expectedClusters := []cluster{{Timecode: 1}, {Timecode: 2}}
+		if !reflect.DeepEqual(expectedClusters, ret.Segment.Cluster) {
+			t.Errorf("Expected Cluster: %+v, got: %+v", expectedClusters, ret.Segment.Cluster)
+		}
+		if r.read > 3*bufferedReaderSize {
+			t.Errorf("Binary payloads are expected to be skipped by seeking, but read %d bytes", r.read)
+		}
+	})
+	t.Run("LazyBinary", func(t *testing.T) {
+		var ret struct {
+			Segment struct {
+				Tracks struct {
+					TrackEntry []struct {
+						CodecPrivate LazyBinary
+					}
+				}
+			}
+		}
+		if err := Unmarshal(bytes.NewReader(b), &ret, WithSkipBinary()); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		if n := len(ret.Segment.Tracks.TrackEntry); n != 1 {
+			t.Fatalf("Expected 1 TrackEntry, got: %d", n)
+		}
+		if size := ret.Segment.Tracks.TrackEntry[0].CodecPrivate.Size(); size != 0x10000 {
+			t.Errorf("Expected CodecPrivate size: %d, got: %d", 0x10000, size)
+		}
+	})
+	t.Run("Chan", func(t *testing.T) {
+		ch := make(chan Block, 2)
+		var blocks struct {
+			Segment struct {
+				Cluster struct {
+					SimpleBlock chan Block
+				}
+			}
+		}
+		blocks.Segment.Cluster.SimpleBlock = ch
+		if err := Unmarshal(bytes.NewReader(b), &blocks, WithSkipBinary()); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		if n := len(ch); n != 0 {
+			t.Errorf("Skipped blocks must not be sent, got %d blocks", n)
+		}
+	})
+}
+
+func TestUnmarshal_Meta(t *testing.T) {
+	type cluster struct {
+		Offset     int64  `ebml:",offset"`
+		DataOffset uint64 `ebml:",dataoffset"`
+		Size       uint64 `ebml:",size"`
+		Raw        []byte `ebml:",raw"`
+		Timecode   uint64
+	}
+	type result struct {
+		Segment struct {
+			DataOffset int `ebml:",dataoffset"`
+			Cluster    []cluster
+		}
+	}
+
+	testCases := map[string]struct {
+		b        []byte
+		expected result
+	}{
+		"KnownSize": {
+			b: []byte{
+				0x18, 0x53, 0x80, 0x67, 0x90,
+				0x1F, 0x43, 0xB6, 0x75, 0x83, 0xE7, 0x81, 0x01,
+				0x1F, 0x43, 0xB6, 0x75, 0x83, 0xE7, 0x81, 0x02,
+			},
+			expected: func() result {
+				var r result
+				r.Segment.DataOffset = 5
+				r.Segment.Cluster = []cluster{
+					{
+						Offset: 5, DataOffset: 10, Size: 3, Timecode: 1,
+						Raw: []byte{0x1F, 0x43, 0xB6, 0x75, 0x83, 0xE7, 0x81, 0x01},
+					},
+					{
+						Offset: 13, DataOffset: 18, Size: 3, Timecode: 2,
+						Raw: []byte{0x1F, 0x43, 0xB6, 0x75, 0x83, 0xE7, 0x81, 0x02},
+					},
+				}
+				return r
+			}(),
+		},
+		"UnknownSize": {
+			b: []byte{
+				0x18, 0x53, 0x80, 0x67, 0xFF,
+				0x1F, 0x43, 0xB6, 0x75, 0xFF, 0xE7, 0x81, 0x01,
+				0x1F, 0x43, 0xB6, 0x75, 0xFF, 0xE7, 0x81, 0x02,
+			},
+			expected: func() result {
+				var r result
+				r.Segment.DataOffset = 5
+				r.Segment.Cluster = []cluster{
+					{
+						Offset: 5, DataOffset: 10, Size: 3, Timecode: 1,
+						Raw: []byte{0x1F, 0x43, 0xB6, 0x75, 0xFF, 0xE7, 0x81, 0x01},
+					},
+					{
+						Offset: 13, DataOffset: 18, Size: 3, Timecode: 2,
+						Raw: []byte{0x1F, 0x43, 0xB6, 0x75, 0xFF, 0xE7, 0x81, 0x02},
+					},
+				}
+				return r
+			}(),
+		},
+	}
+	for name, c := range testCases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			runForEachReader(t, c.b, func(t *testing.T, r func() io.Reader) {
+				var ret result
+				if err := Unmarshal(r(), &ret); err != nil {
+					t.Fatalf("Unexpected error: '%v'", err)
+				}
+				if !reflect.DeepEqual(c.expected, ret) {
+					t.Errorf("Expected result: %+v, got: %+v", c.expected, ret)
+				}
+			})
+		})
+	}
+}
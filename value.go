@@ -16,10 +16,12 @@ package ebml
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"io"
 	"math"
+	"reflect"
 	"strings"
 	"time"
 )
@@ -43,10 +45,25 @@ var ErrUnsupportedElementID = errors.New("unsupported Element ID")
 // ErrOutOfRange means that a value is out of range of the data type.
 var ErrOutOfRange = errors.New("out of range")
 
+// ErrInvalidVINT means that a variable length integer has no length marker.
+var ErrInvalidVINT = errors.New("invalid variable length integer")
+
 // valueDecoder is a value decoder sharing internal buffer.
 // Member functions must not called concurrently.
 type valueDecoder struct {
 	bs [1]byte
+	// readerAt is the source of the stream to read binaries lazily.
+	readerAt io.ReaderAt
+	// offset is the offset of the stream in readerAt.
+	offset int64
+	// timecodeScale is the TimecodeScale of the Segment being read.
+	timecodeScale uint64
+	// strict rejects variable length integers without length marker.
+	strict bool
+	// stopDepth is the depth of the element which stopped the last read.
+	stopDepth int
+	// ctx stops the read if done.
+	ctx context.Context
 }
 
 func (d *valueDecoder) decode(t DataType, r io.Reader, n uint64) (interface{}, error) {
@@ -78,19 +95,19 @@ func (d *valueDecoder) readDataSize(r io.Reader) (uint64, int, error) {
 }
 
 func (d *valueDecoder) readVUInt(r io.Reader) (uint64, int, error) {
-	bytesRead, err := r.Read(d.bs[:])
+	b, err := d.readByte(r)
 	switch err {
 	case nil:
 	case io.EOF:
-		return 0, bytesRead, io.ErrUnexpectedEOF
+		return 0, 0, io.ErrUnexpectedEOF
 	default:
-		return 0, bytesRead, err
+		return 0, 0, err
 	}
+	bytesRead := 1
 
 	var vc int
 	var value uint64
 
-	b := d.bs[0]
 	switch {
 	case b&0x80 == 0x80:
 		vc = 0
@@ -116,6 +133,8 @@ func (d *valueDecoder) readVUInt(r io.Reader) (uint64, int, error) {
 	case b == 0x01:
 		vc = 7
 		value = 0
+	case d.strict:
+		return 0, bytesRead, ErrInvalidVINT
 	}
 
 	for {
@@ -123,7 +142,7 @@ func (d *valueDecoder) readVUInt(r io.Reader) (uint64, int, error) {
 			return value, bytesRead, nil
 		}
 
-		n, err := r.Read(d.bs[:])
+		b, err := d.readByte(r)
 		switch err {
 		case nil:
 		case io.EOF:
@@ -131,12 +150,24 @@ func (d *valueDecoder) readVUInt(r io.Reader) (uint64, int, error) {
 		default:
 			return 0, bytesRead, err
 		}
-		bytesRead += n
-		value = value<<8 | uint64(d.bs[0])
+		bytesRead++
+		value = value<<8 | uint64(b)
 		vc--
 	}
 }
 
+// readByte reads one byte.
+// io.ByteReader is used if implemented to avoid small reads on the source.
+func (d *valueDecoder) readByte(r io.Reader) (byte, error) {
+	if br, ok := r.(io.ByteReader); ok {
+		return br.ReadByte()
+	}
+	if _, err := r.Read(d.bs[:]); err != nil {
+		return 0, err
+	}
+	return d.bs[0], nil
+}
+
 func (d *valueDecoder) readVInt(r io.Reader) (int64, int, error) {
 	u, n, err := d.readVUInt(r)
 	if err != nil {
@@ -165,9 +196,7 @@ func (d *valueDecoder) readVInt(r io.Reader) (int64, int, error) {
 }
 
 func (d *valueDecoder) readBinary(r io.Reader, n uint64) (interface{}, error) {
-	bs := make([]byte, n)
-
-	switch _, err := io.ReadFull(r, bs); err {
+	switch bs, err := readSlice(r, n); err {
 	case nil:
 		return bs, nil
 	case io.EOF:
@@ -204,9 +233,8 @@ func (d *valueDecoder) readInt(r io.Reader, n uint64) (interface{}, error) {
 }
 
 func (d *valueDecoder) readUInt(r io.Reader, n uint64) (interface{}, error) {
-	bs := make([]byte, n)
-
-	switch _, err := io.ReadFull(r, bs); err {
+	bs, err := readSlice(r, n)
+	switch err {
 	case nil:
 	case io.EOF:
 		return 0, io.ErrUnexpectedEOF
@@ -230,9 +258,8 @@ func (d *valueDecoder) readDate(r io.Reader, n uint64) (interface{}, error) {
 }
 
 func (d *valueDecoder) readFloat(r io.Reader, n uint64) (interface{}, error) {
-	bs := make([]byte, n)
-
-	switch _, err := io.ReadFull(r, bs); err {
+	bs, err := readSlice(r, n)
+	switch err {
 	case nil:
 	case io.EOF:
 		return bs, io.ErrUnexpectedEOF
@@ -291,6 +318,24 @@ func encodeDataSize(v, n uint64) []byte {
 	}
 }
 
+// encodeUnknownDataSize returns n bytes unknown data size.
+func encodeUnknownDataSize(n int) []byte {
+	b := bytes.Repeat([]byte{0xFF}, n)
+	b[0] = 0xFF >> uint(n-1)
+	return b
+}
+
+// elementHeader returns the element ID and n bytes data size of the element.
+func elementHeader(t ElementType, size uint64, n int) []byte {
+	var bsz []byte
+	if size == SizeUnknown {
+		bsz = encodeUnknownDataSize(n)
+	} else {
+		bsz = encodeDataSize(size, uint64(n))
+	}
+	return bytes.Join([][]byte{table[t].b, bsz}, []byte{})
+}
+
 func encodeElementID(v uint64) ([]byte, error) {
 	switch {
 	case v < 0x80:
@@ -345,7 +390,12 @@ func encodeVInt(v int64) ([]byte, error) {
 func encodeBinary(i interface{}, n uint64) ([]byte, error) {
 	v, ok := i.([]byte)
 	if !ok {
-		return []byte{}, ErrInvalidType
+		vi := reflect.ValueOf(i)
+		if !vi.IsValid() || !isByteArray(vi.Type()) {
+			return []byte{}, ErrInvalidType
+		}
+		v = make([]byte, vi.Len())
+		reflect.Copy(reflect.ValueOf(v), vi)
 	}
 	if uint64(len(v)) >= n {
 		return v, nil
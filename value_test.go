@@ -289,7 +289,7 @@ func TestEncodeValue_WrongInputType(t *testing.T) {
 	}{
 		{
 			DataTypeBinary,
-			[]interface{}{"aaa", int64(1), uint64(1), time.Unix(1, 0), float32(1.0), float64(1.0), Block{}},
+			[]interface{}{"aaa", int64(1), uint64(1), time.Unix(1, 0), float32(1.0), float64(1.0), Block{}, nil},
 			ErrInvalidType,
 		},
 		{
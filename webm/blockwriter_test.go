@@ -192,3 +192,67 @@ func TestBlockWriter_NewSimpleWriter(t *testing.T) {
 		t.Errorf("Unexpected WebM binary,\nexpected: %+v\n     got: %+v", expectedBytes, buf.Bytes())
 	}
 }
+
+func TestBlockWriter_BlockAdditions(t *testing.T) {
+	buf := buffercloser.New()
+
+	tracks := []TrackEntry{
+		{
+			TrackNumber:        1,
+			TrackUID:           12345,
+			CodecID:            "V_VP9",
+			TrackType:          1,
+			Video:              &Video{PixelWidth: 320, PixelHeight: 240},
+			MaxBlockAdditionID: 1,
+			BlockAdditionMapping: []BlockAdditionMapping{
+				{BlockAddIDValue: 1, BlockAddIDType: 0},
+			},
+		},
+	}
+	ws, err := NewSimpleBlockWriter(buf, tracks)
+	if err != nil {
+		t.Fatalf("Failed to create BlockWriter: %v", err)
+	}
+
+	frames := []mkvcore.Frame{
+		{Keyframe: true, Timestamp: 0, Data: []byte{0x01}, Additions: map[uint64][]byte{1: {0x02}}},
+		{Keyframe: false, Timestamp: 33, Data: []byte{0x03}},
+	}
+	for _, f := range frames {
+		if _, err := ws[0].(mkvcore.FrameWriter).WriteFrame(f); err != nil {
+			t.Fatalf("Failed to WriteFrame: %v", err)
+		}
+	}
+	ws[0].Close()
+
+	var result struct {
+		Header  EBMLHeader `ebml:"EBML"`
+		Segment Segment    `ebml:"Segment,size=unknown"`
+	}
+	if err := ebml.Unmarshal(bytes.NewReader(buf.Bytes()), &result); err != nil {
+		t.Fatalf("Failed to Unmarshal resultant binary: %v", err)
+	}
+	if !reflect.DeepEqual(tracks, result.Segment.Tracks.TrackEntry) {
+		t.Errorf("Unexpected TrackEntry,\nexpected: %+v\n     got: %+v", tracks, result.Segment.Tracks.TrackEntry)
+	}
+	expected := Cluster{
+		Timecode: 0,
+		BlockGroup: []BlockGroup{
+			{
+				Block: ebml.Block{TrackNumber: 1, Timecode: 0, Data: [][]byte{{0x01}}},
+				BlockAdditions: &BlockAdditions{
+					BlockMore: []BlockMore{{BlockAddID: 1, BlockAdditional: []byte{0x02}}},
+				},
+			},
+		},
+		SimpleBlock: []ebml.Block{
+			{TrackNumber: 1, Timecode: 33, Data: [][]byte{{0x03}}},
+		},
+	}
+	if len(result.Segment.Cluster) == 0 {
+		t.Fatal("Cluster not found")
+	}
+	if !reflect.DeepEqual(expected, result.Segment.Cluster[0]) {
+		t.Errorf("Unexpected Cluster,\nexpected: %+v\n     got: %+v", expected, result.Segment.Cluster[0])
+	}
+}
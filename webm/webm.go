@@ -47,11 +47,12 @@ type SeekHead struct {
 
 // Info represents Info element struct.
 type Info struct {
-	TimecodeScale uint64    `ebml:"TimecodeScale"`
-	MuxingApp     string    `ebml:"MuxingApp,omitempty"`
-	WritingApp    string    `ebml:"WritingApp,omitempty"`
-	Duration      float64   `ebml:"Duration,omitempty"`
-	DateUTC       time.Time `ebml:"DateUTC,omitempty"`
+	SegmentUID    ebml.UID128 `ebml:"SegmentUID,omitempty"`
+	TimecodeScale uint64      `ebml:"TimecodeScale"`
+	MuxingApp     string      `ebml:"MuxingApp,omitempty"`
+	WritingApp    string      `ebml:"WritingApp,omitempty"`
+	Duration      float64     `ebml:"Duration,omitempty"`
+	DateUTC       time.Time   `ebml:"DateUTC,omitempty"`
 }
 
 // SetDuration sets the Duration field.
@@ -72,6 +73,17 @@ type TrackEntry struct {
 	SeekPreRoll     uint64 `ebml:"SeekPreRoll,omitempty"`
 	Audio           *Audio `ebml:"Audio"`
 	Video           *Video `ebml:"Video"`
+
+	MaxBlockAdditionID   uint64                 `ebml:"MaxBlockAdditionID,omitempty"`
+	BlockAdditionMapping []BlockAdditionMapping `ebml:"BlockAdditionMapping,omitempty"`
+}
+
+// BlockAdditionMapping represents BlockAdditionMapping element struct.
+type BlockAdditionMapping struct {
+	BlockAddIDValue     uint64 `ebml:"BlockAddIDValue,omitempty"`
+	BlockAddIDName      string `ebml:"BlockAddIDName,omitempty"`
+	BlockAddIDType      uint64 `ebml:"BlockAddIDType"`
+	BlockAddIDExtraData []byte `ebml:"BlockAddIDExtraData,omitempty"`
 }
 
 // Audio represents Audio element struct.
@@ -91,11 +103,24 @@ type Tracks struct {
 	TrackEntry []TrackEntry `ebml:"TrackEntry"`
 }
 
+// BlockMore represents BlockMore element struct.
+type BlockMore struct {
+	BlockAddID      uint64 `ebml:"BlockAddID"`
+	BlockAdditional []byte `ebml:"BlockAdditional"`
+}
+
+// BlockAdditions represents BlockAdditions element struct.
+// WebM stores the alpha channel of VP8/VP9 video in BlockMore with BlockAddID 1.
+type BlockAdditions struct {
+	BlockMore []BlockMore `ebml:"BlockMore"`
+}
+
 // BlockGroup represents BlockGroup element struct.
 type BlockGroup struct {
-	BlockDuration  uint64     `ebml:"BlockDuration,omitempty"`
-	ReferenceBlock int64      `ebml:"ReferenceBlock,omitempty"`
-	Block          ebml.Block `ebml:"Block"`
+	BlockDuration  uint64          `ebml:"BlockDuration,omitempty"`
+	ReferenceBlock int64           `ebml:"ReferenceBlock,omitempty"`
+	Block          ebml.Block      `ebml:"Block"`
+	BlockAdditions *BlockAdditions `ebml:"BlockAdditions,omitempty"`
 }
 
 // Cluster represents Cluster element struct.